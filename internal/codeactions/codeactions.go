@@ -0,0 +1,333 @@
+// Package for turning diagnostics into quick fixes
+package codeactions
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	schemautils "github.com/loczek/nomad-ls/internal/schemaUtils"
+	"github.com/loczek/nomad-ls/internal/variables"
+)
+
+// TextEdit replaces text within Range with NewText
+type TextEdit struct {
+	Range   hcl.Range
+	NewText string
+}
+
+// QuickFix is a set of edits resolving diagnostics
+type QuickFix struct {
+	Title       string
+	Diagnostics hcl.Diagnostics
+	Edits       []TextEdit
+}
+
+type successor struct {
+	Name string
+	// AsList wraps the value in a list, e.g. `cron = "..."` becomes `crons = ["..."]`
+	AsList bool
+}
+
+// deprecatedAttributeSuccessors maps deprecated attributes to attributes replacing them
+var deprecatedAttributeSuccessors = map[string]successor{
+	"cron": {Name: "crons", AsList: true},
+}
+
+// node is an attribute or a block body of the file together with the schema
+// of the body containing the attribute or of the block body
+type node struct {
+	attr       *hclsyntax.Attribute
+	block      *hclsyntax.Block
+	bodySchema *schema.BodySchema
+}
+
+// QuickFixes returns fixes of diagnostics, hcl-lang diagnostics carry no codes so
+// their subjects are matched against ranges of attributes and block bodies instead
+// and the schema tells whether an attribute is unexpected, deprecated or missing
+func QuickFixes(file *hcl.File, bodySchema *schema.BodySchema, diags hcl.Diagnostics) []QuickFix {
+	fixes := make([]QuickFix, 0)
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return fixes
+	}
+
+	nodes := make(map[hcl.Range]node)
+	indexNodes(body, bodySchema, nodes)
+
+	// missing required attributes are all reported with the range of the block body
+	bodies := make([]hcl.Range, 0)
+	bodyDiags := make(map[hcl.Range]hcl.Diagnostics)
+
+	for _, diag := range diags {
+		if diag.Subject == nil {
+			continue
+		}
+
+		n, ok := nodes[*diag.Subject]
+		switch {
+		case ok && n.attr != nil:
+			fixes = append(fixes, attributeFixes(file.Bytes, n, diag)...)
+		case ok && n.block != nil && diag.Severity == hcl.DiagError:
+			if _, seen := bodyDiags[*diag.Subject]; !seen {
+				bodies = append(bodies, *diag.Subject)
+			}
+			bodyDiags[*diag.Subject] = append(bodyDiags[*diag.Subject], diag)
+		case !ok && diag.Severity == hcl.DiagError:
+			fixes = append(fixes, undeclaredVariable(file.Bytes, body, diag)...)
+		}
+	}
+
+	for _, rng := range bodies {
+		fixes = append(fixes, missingRequiredAttributes(file.Bytes, nodes[rng], bodyDiags[rng])...)
+	}
+
+	return fixes
+}
+
+// indexNodes adds attributes and block bodies with known schemas to nodes by their ranges
+func indexNodes(body *hclsyntax.Body, bodySchema *schema.BodySchema, nodes map[hcl.Range]node) {
+	if bodySchema == nil {
+		return
+	}
+
+	for _, attr := range body.Attributes {
+		nodes[attr.SrcRange] = node{attr: attr, bodySchema: bodySchema}
+	}
+
+	for _, block := range body.Blocks {
+		bSchema, ok := bodySchema.Blocks[block.Type]
+		if !ok {
+			continue
+		}
+
+		blockBodySchema := schemautils.BlockBodySchema(block, bSchema)
+		if blockBodySchema == nil {
+			continue
+		}
+
+		nodes[block.Body.SrcRange] = node{block: block, bodySchema: blockBodySchema}
+		indexNodes(block.Body, blockBodySchema, nodes)
+	}
+}
+
+// attributeFixes returns fixes of an unexpected or a deprecated attribute
+func attributeFixes(src []byte, n node, diag *hcl.Diagnostic) []QuickFix {
+	if n.bodySchema.AnyAttribute != nil {
+		return nil
+	}
+
+	attrSchema, ok := n.bodySchema.Attributes[n.attr.Name]
+	switch {
+	case !ok && diag.Severity == hcl.DiagError:
+		return unexpectedAttribute(src, diag, n.attr.Name)
+	case ok && attrSchema.IsDeprecated && diag.Severity == hcl.DiagWarning:
+		return deprecatedAttribute(src, n.attr, diag)
+	}
+
+	return nil
+}
+
+// missingRequiredAttributes returns a fix for each required attribute missing in the block,
+// each resolving all diagnostics of the block body
+func missingRequiredAttributes(src []byte, n node, diags hcl.Diagnostics) []QuickFix {
+	fixes := make([]QuickFix, 0)
+
+	names := slices.Sorted(maps.Keys(n.bodySchema.Attributes))
+	for _, name := range names {
+		attrSchema := n.bodySchema.Attributes[name]
+		if _, ok := n.block.Body.Attributes[name]; ok || !attrSchema.IsRequired {
+			continue
+		}
+
+		fixes = append(fixes, missingRequiredAttribute(src, n.block, attrSchema, diags, name))
+	}
+
+	return fixes
+}
+
+func missingRequiredAttribute(src []byte, block *hclsyntax.Block, attrSchema *schema.AttributeSchema, diags hcl.Diagnostics, name string) QuickFix {
+	blockIndent := lineIndent(src, block.TypeRange.Start.Byte)
+	bodyStart := block.Body.SrcRange.Start
+	insertPos := hcl.Pos{Line: bodyStart.Line, Column: bodyStart.Column + 1, Byte: bodyStart.Byte + 1}
+
+	newText := fmt.Sprintf("\n%s  %s = %s", blockIndent, name, attributeValue(attrSchema))
+	if block.Body.SrcRange.Start.Line == block.Body.SrcRange.End.Line {
+		newText += "\n" + blockIndent
+	}
+
+	return QuickFix{
+		Title:       fmt.Sprintf("Add required attribute %q", name),
+		Diagnostics: diags,
+		Edits: []TextEdit{
+			{
+				Range:   hcl.Range{Filename: block.Body.SrcRange.Filename, Start: insertPos, End: insertPos},
+				NewText: newText,
+			},
+		},
+	}
+}
+
+func unexpectedAttribute(src []byte, diag *hcl.Diagnostic, name string) []QuickFix {
+	return []QuickFix{
+		{
+			Title:       fmt.Sprintf("Remove unexpected attribute %q", name),
+			Diagnostics: hcl.Diagnostics{diag},
+			Edits: []TextEdit{
+				{
+					Range:   wholeLines(src, *diag.Subject),
+					NewText: "",
+				},
+			},
+		},
+	}
+}
+
+func deprecatedAttribute(src []byte, attr *hclsyntax.Attribute, diag *hcl.Diagnostic) []QuickFix {
+	name := attr.Name
+
+	succ, ok := deprecatedAttributeSuccessors[name]
+	if !ok {
+		return []QuickFix{
+			{
+				Title:       fmt.Sprintf("Remove deprecated attribute %q", name),
+				Diagnostics: hcl.Diagnostics{diag},
+				Edits: []TextEdit{
+					{
+						Range:   wholeLines(src, *diag.Subject),
+						NewText: "",
+					},
+				},
+			},
+		}
+	}
+
+	exprRange := attr.Expr.Range()
+	value := string(exprRange.SliceBytes(src))
+	if _, isList := attr.Expr.(*hclsyntax.TupleConsExpr); succ.AsList && !isList {
+		value = "[" + value + "]"
+	}
+
+	return []QuickFix{
+		{
+			Title:       fmt.Sprintf("Replace %q with %q", name, succ.Name),
+			Diagnostics: hcl.Diagnostics{diag},
+			Edits: []TextEdit{
+				{
+					Range:   attr.SrcRange,
+					NewText: fmt.Sprintf("%s = %s", succ.Name, value),
+				},
+			},
+		},
+	}
+}
+
+// undeclaredVariable returns a fix declaring the variable referenced at the subject
+// of the diagnostic unless the body declares it already
+func undeclaredVariable(src []byte, body *hclsyntax.Body, diag *hcl.Diagnostic) []QuickFix {
+	if diag.Subject.End.Byte > len(src) {
+		return nil
+	}
+
+	traversal, diags := hclsyntax.ParseTraversalAbs(diag.Subject.SliceBytes(src), diag.Subject.Filename, diag.Subject.Start)
+	if diags.HasErrors() || len(traversal) < 2 || traversal.RootName() != "var" {
+		return nil
+	}
+
+	step, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return nil
+	}
+
+	name := step.Name
+	declared := slices.ContainsFunc(variables.Declared(body), func(v variables.Variable) bool {
+		return v.Name == name
+	})
+	if declared {
+		return nil
+	}
+
+	return []QuickFix{
+		{
+			Title:       fmt.Sprintf("Declare variable %q", name),
+			Diagnostics: hcl.Diagnostics{diag},
+			Edits: []TextEdit{
+				{
+					Range:   hcl.Range{Filename: diag.Subject.Filename, Start: hcl.InitialPos, End: hcl.InitialPos},
+					NewText: fmt.Sprintf("variable %q {\n}\n\n", name),
+				},
+			},
+		},
+	}
+}
+
+// attributeValue returns the schema default of the attribute
+// or an empty value of its type
+func attributeValue(attrSchema *schema.AttributeSchema) string {
+	if def, ok := attrSchema.DefaultValue.(schema.DefaultValue); ok && def.Value.Type() != cty.NilType && !def.Value.IsNull() {
+		return string(hclwrite.TokensForValue(def.Value).Bytes())
+	}
+
+	typ := constraintType(attrSchema.Constraint)
+
+	switch {
+	case typ == cty.Number:
+		return "0"
+	case typ == cty.Bool:
+		return "false"
+	case typ.IsListType() || typ.IsSetType() || typ.IsTupleType():
+		return "[]"
+	case typ.IsMapType() || typ.IsObjectType():
+		return "{}"
+	default:
+		return `""`
+	}
+}
+
+func constraintType(cons schema.Constraint) cty.Type {
+	switch c := cons.(type) {
+	case schema.LiteralType:
+		return c.Type
+	case schema.AnyExpression:
+		return c.OfType
+	case schema.OneOf:
+		for _, inner := range c {
+			if typ := constraintType(inner); typ != cty.DynamicPseudoType {
+				return typ
+			}
+		}
+	}
+
+	return cty.DynamicPseudoType
+}
+
+// wholeLines extends rng to cover whole lines including the trailing newline
+func wholeLines(src []byte, rng hcl.Range) hcl.Range {
+	startByte := bytes.LastIndexByte(src[:rng.Start.Byte], '\n') + 1
+
+	endByte := len(src)
+	if idx := bytes.IndexByte(src[rng.End.Byte:], '\n'); idx != -1 {
+		endByte = rng.End.Byte + idx + 1
+	}
+
+	return hcl.Range{
+		Filename: rng.Filename,
+		Start:    hcl.Pos{Line: rng.Start.Line, Column: 1, Byte: startByte},
+		End:      hcl.Pos{Line: rng.End.Line + 1, Column: 1, Byte: endByte},
+	}
+}
+
+func lineIndent(src []byte, offset int) string {
+	start := bytes.LastIndexByte(src[:offset], '\n') + 1
+	line := src[start:offset]
+
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
@@ -0,0 +1,143 @@
+package codeactions
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/store"
+	"github.com/loczek/nomad-ls/internal/validation"
+)
+
+const QUICK_FIXES_NOMAD_FILE_PATH = "./testdata/quick_fixes.nomad.hcl"
+
+func TestQuickFixes(t *testing.T) {
+	src, err := os.ReadFile(QUICK_FIXES_NOMAD_FILE_PATH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := store.NewStore()
+	doc := store.NewDocument(languages.NomadJob)
+	doc.ParseHCL(src, QUICK_FIXES_NOMAD_FILE_PATH)
+	s.AddFile(QUICK_FIXES_NOMAD_FILE_PATH, doc)
+
+	langPath := lang.Path{
+		Path:       QUICK_FIXES_NOMAD_FILE_PATH,
+		LanguageID: languages.NomadJob.String(),
+	}
+
+	pathDec, err := decoder.NewDecoder(&s).Path(langPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc.UpdateReferences(pathDec, QUICK_FIXES_NOMAD_FILE_PATH)
+
+	diags, err := pathDec.ValidateFile(context.Background(), QUICK_FIXES_NOMAD_FILE_PATH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pathCtx, err := s.PathContext(langPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range validation.UnreferencedOrigins(context.Background(), pathCtx) {
+		diags = diags.Extend(d)
+	}
+
+	bodySchema := languages.ToSchema(languages.NomadJob)
+	fixes := QuickFixes(doc.File(), &bodySchema, diags)
+
+	expected := map[string]string{
+		`Add required attribute "driver"`:       "\n      driver = \"\"",
+		`Remove unexpected attribute "unknown"`: "",
+		`Replace "cron" with "crons"`:           "crons = [\"@daily\"]",
+		`Declare variable "image"`:              "variable \"image\" {\n}\n\n",
+	}
+
+	titles := make([]string, 0)
+	for _, fix := range fixes {
+		titles = append(titles, fix.Title)
+
+		newText, ok := expected[fix.Title]
+		if !ok {
+			continue
+		}
+
+		if len(fix.Edits) != 1 || fix.Edits[0].NewText != newText {
+			t.Errorf("%s: expected edit %q, recieved: %+v", fix.Title, newText, fix.Edits)
+		}
+	}
+
+	for title := range expected {
+		if !slices.Contains(titles, title) {
+			t.Errorf("expected quick fix %q, recieved: %v", title, titles)
+		}
+	}
+}
+
+func TestWholeLines(t *testing.T) {
+	src := []byte("a {\n  b = 1\n}\n")
+	rng := hcl.Range{
+		Start: hcl.Pos{Line: 2, Column: 3, Byte: 6},
+		End:   hcl.Pos{Line: 2, Column: 8, Byte: 11},
+	}
+
+	lines := wholeLines(src, rng)
+
+	if string(lines.SliceBytes(src)) != "  b = 1\n" {
+		t.Errorf("expected whole line, recieved: %q", lines.SliceBytes(src))
+	}
+}
+
+func TestQuickFixesMatchRanges(t *testing.T) {
+	src, err := os.ReadFile(QUICK_FIXES_NOMAD_FILE_PATH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, diags := hclsyntax.ParseConfig(src, QUICK_FIXES_NOMAD_FILE_PATH, hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	task := file.Body.(*hclsyntax.Body).Blocks[0].Body.Blocks[1].Body.Blocks[0]
+	bodySchema := languages.ToSchema(languages.NomadJob)
+
+	tests := map[string]struct {
+		subject hcl.Range
+		titles  []string
+	}{
+		"unexpected attribute": {task.Body.Attributes["unknown"].SrcRange, []string{`Remove unexpected attribute "unknown"`}},
+		"expected attribute":   {task.Body.Attributes["driver"].SrcRange, []string{}},
+		"value of attribute":   {task.Body.Attributes["unknown"].Expr.Range(), []string{}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// fixes do not depend on wording of diagnostics
+			fixes := QuickFixes(file, &bodySchema, hcl.Diagnostics{
+				{Severity: hcl.DiagError, Summary: "Reworded summary", Subject: test.subject.Ptr()},
+			})
+
+			titles := make([]string, 0)
+			for _, fix := range fixes {
+				titles = append(titles, fix.Title)
+			}
+
+			if !slices.Equal(titles, test.titles) {
+				t.Errorf("expected %v, recieved: %v", test.titles, titles)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+job "example" {
+  periodic {
+    cron = "@daily"
+  }
+
+  group "app" {
+    task "server" {
+      driver  = "docker"
+      unknown = "value"
+
+      config {
+        image = var.image
+      }
+    }
+
+    task "worker" {
+      user = "nobody"
+    }
+  }
+}
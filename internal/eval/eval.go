@@ -0,0 +1,39 @@
+// Package for static evaluation of expressions of Nomad files
+package eval
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	funcs "github.com/loczek/nomad-ls/internal/function"
+)
+
+// NewContext makes contexts evaluating `var.<name>` references to the variables
+// and function calls with the function table, relative paths are resolved against basedir
+type NewContext func(basedir string, variables map[string]cty.Value) *hcl.EvalContext
+
+var (
+	_ NewContext = Context
+	_ NewContext = RenderContext
+)
+
+// Context returns the context of diagnostics and hover, calls of impure functions
+// only check their arguments so that results do not depend on files or randomness
+func Context(basedir string, variables map[string]cty.Value) *hcl.EvalContext {
+	return &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(variables),
+		},
+		Functions: funcs.PureImplementations(basedir),
+	}
+}
+
+// RenderContext returns the context evaluating every function the same way as Nomad
+func RenderContext(basedir string, variables map[string]cty.Value) *hcl.EvalContext {
+	return &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(variables),
+		},
+		Functions: funcs.Implementations(basedir),
+	}
+}
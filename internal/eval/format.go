@@ -0,0 +1,68 @@
+package eval
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// FormatValue formats a known value in the HCL syntax
+func FormatValue(val cty.Value) string {
+	return string(hclwrite.Format(hclwrite.TokensForValue(val).Bytes()))
+}
+
+// Partial formats the value of the expression, parts of templates which can not
+// be evaluated statically are kept as written, e.g. `"${node.unique.name}-api"`.
+// Unresolved references are returned as written in src.
+func Partial(expr hclsyntax.Expression, ctx *hcl.EvalContext, src []byte) (string, []string) {
+	unresolved := make([]string, 0)
+	for _, traversal := range expr.Variables() {
+		if val, diags := traversal.TraverseAbs(ctx); diags.HasErrors() || !val.IsWhollyKnown() {
+			name := string(traversal.SourceRange().SliceBytes(src))
+			if !slices.Contains(unresolved, name) {
+				unresolved = append(unresolved, name)
+			}
+		}
+	}
+
+	if val, diags := expr.Value(ctx); !diags.HasErrors() && val.IsWhollyKnown() {
+		return FormatValue(val), unresolved
+	}
+
+	var parts []hclsyntax.Expression
+	switch e := expr.(type) {
+	case *hclsyntax.TemplateExpr:
+		parts = e.Parts
+	case *hclsyntax.TemplateWrapExpr:
+		parts = []hclsyntax.Expression{e.Wrapped}
+	default:
+		return string(expr.Range().SliceBytes(src)), unresolved
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`"`)
+	for _, part := range parts {
+		if lit, ok := part.(*hclsyntax.LiteralValueExpr); ok && lit.Val.Type() == cty.String {
+			sb.WriteString(lit.Val.AsString())
+			continue
+		}
+
+		val, diags := part.Value(ctx)
+		if !diags.HasErrors() && val.IsWhollyKnown() && !val.IsNull() {
+			if str, err := convert.Convert(val, cty.String); err == nil {
+				sb.WriteString(str.AsString())
+				continue
+			}
+		}
+
+		sb.WriteString("${" + string(part.Range().SliceBytes(src)) + "}")
+	}
+	sb.WriteString(`"`)
+
+	return sb.String(), unresolved
+}
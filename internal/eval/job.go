@@ -0,0 +1,60 @@
+package eval
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// JobContext adds `local.<name>` references of a job file to the context,
+// locals which can not be evaluated are unknown
+func JobContext(ctx *hcl.EvalContext, body *hclsyntax.Body) *hcl.EvalContext {
+	exprs := Locals(body)
+	values := make(map[string]cty.Value, len(exprs))
+	for name := range exprs {
+		values[name] = cty.DynamicVal
+	}
+
+	// locals referencing other locals are resolved over multiple passes
+	for range len(exprs) {
+		ctx.Variables["local"] = cty.ObjectVal(values)
+
+		changed := false
+		for name, expr := range exprs {
+			val, diags := expr.Value(ctx)
+			if diags.HasErrors() {
+				val = cty.DynamicVal
+			}
+
+			if !val.RawEquals(values[name]) {
+				values[name] = val
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	ctx.Variables["local"] = cty.ObjectVal(values)
+
+	return ctx
+}
+
+// Locals returns expressions of locals declared by `locals` blocks of the body
+func Locals(body *hclsyntax.Body) map[string]hclsyntax.Expression {
+	exprs := make(map[string]hclsyntax.Expression)
+
+	for _, block := range body.Blocks {
+		if block.Type != "locals" {
+			continue
+		}
+
+		for name, attr := range block.Body.Attributes {
+			exprs[name] = attr.Expr
+		}
+	}
+
+	return exprs
+}
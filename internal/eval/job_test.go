@@ -0,0 +1,76 @@
+package eval_test
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+	"github.com/loczek/nomad-ls/internal/variables"
+)
+
+func parse(t *testing.T, path string) ([]byte, *hclsyntax.Body) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, diags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	return src, file.Body.(*hclsyntax.Body)
+}
+
+func TestJobContext(t *testing.T) {
+	_, body := parse(t, "testdata/locals.nomad.hcl")
+
+	ctx := eval.JobContext(eval.Context("testdata", variables.Values(eval.Context, variables.Declared(body), nil)), body)
+	locals := ctx.Variables["local"]
+
+	expected := map[string]cty.Value{
+		"name":    cty.StringVal("web"),
+		"service": cty.StringVal("web-3"),
+		"tags":    cty.TupleVal([]cty.Value{cty.StringVal("WEB"), cty.StringVal("api")}),
+	}
+
+	for name, val := range expected {
+		if !locals.GetAttr(name).RawEquals(val) {
+			t.Errorf("expected local.%s = %#v, recieved: %#v", name, val, locals.GetAttr(name))
+		}
+	}
+
+	for _, name := range []string{"host", "image"} {
+		if locals.GetAttr(name).IsKnown() {
+			t.Errorf("expected local.%s to be unknown, recieved: %#v", name, locals.GetAttr(name))
+		}
+	}
+}
+
+func TestPartial(t *testing.T) {
+	src, body := parse(t, "testdata/locals.nomad.hcl")
+
+	ctx := eval.JobContext(eval.Context("testdata", variables.Values(eval.Context, variables.Declared(body), nil)), body)
+	locals := eval.Locals(body)
+
+	tests := map[string]struct {
+		value      string
+		unresolved []string
+	}{
+		"service": {`"web-3"`, []string{}},
+		"host":    {`"${node.unique.name}-web"`, []string{"node.unique.name"}},
+		"image":   {`"${var.image}:1.27"`, []string{"var.image"}},
+	}
+
+	for name, test := range tests {
+		value, unresolved := eval.Partial(locals[name], ctx, src)
+		if value != test.value || !slices.Equal(unresolved, test.unresolved) {
+			t.Errorf("local.%s: expected %s %v, recieved: %s %v", name, test.value, test.unresolved, value, unresolved)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package eval
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// StaticValue returns the value of an expression which does not contain any references,
+// it has to be known, not null and of the given type
+func StaticValue(expr hcl.Expression, typ cty.Type) (cty.Value, bool) {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || !val.Type().Equals(typ) {
+		return cty.NilVal, false
+	}
+
+	return val, true
+}
+
+// StaticString returns the value of a string literal,
+// numbers and bools are converted as they are accepted in place of strings
+func StaticString(expr hcl.Expression) (string, bool) {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() {
+		return "", false
+	}
+
+	val, err := convert.Convert(val, cty.String)
+	if err != nil {
+		return "", false
+	}
+
+	return val.AsString(), true
+}
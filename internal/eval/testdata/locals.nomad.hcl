@@ -0,0 +1,22 @@
+variable "count" {
+  type    = number
+  default = 3
+}
+
+variable "image" {
+  type = string
+}
+
+locals {
+  name    = "web"
+  service = "${local.name}-${var.count}"
+  host    = "${node.unique.name}-${local.name}"
+  tags    = [upper(local.name), "api"]
+  image   = "${var.image}:1.27"
+}
+
+job "app" {
+  group "app" {
+    count = var.count
+  }
+}
@@ -411,16 +411,16 @@ var Functions map[string]hclschema.FunctionSignature = map[string]hclschema.Func
 	"index": {
 		Params: []function.Parameter{
 			{
-				Name: "list",
+				Name: "collection",
 				Type: cty.DynamicPseudoType,
 			},
 			{
-				Name: "value",
+				Name: "key",
 				Type: cty.DynamicPseudoType,
 			},
 		},
 		ReturnType:  cty.DynamicPseudoType,
-		Description: "`index` finds the element index for a given value in a list.",
+		Description: "`index` returns the element of a list or a map with the given index or key.",
 	},
 	"join": {
 		Params: []function.Parameter{
@@ -0,0 +1,92 @@
+package funcs_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	funcs "github.com/loczek/nomad-ls/internal/function"
+)
+
+func TestImplementationsMatchTable(t *testing.T) {
+	impls := funcs.Implementations(".")
+
+	for name := range funcs.Functions {
+		if _, ok := impls[name]; !ok {
+			t.Errorf("expected an implementation of %q", name)
+		}
+	}
+
+	for name := range impls {
+		if _, ok := funcs.Functions[name]; !ok {
+			t.Errorf("expected %q in the function table", name)
+		}
+	}
+}
+
+func TestImplementations(t *testing.T) {
+	ctx := &hcl.EvalContext{Functions: funcs.Implementations(".")}
+
+	tests := map[string]cty.Value{
+		`cidrsubnet("10.0.0.0/16", 8, 2)`:       cty.StringVal("10.0.2.0/24"),
+		`format("%s-%d", "web", 2)`:             cty.StringVal("web-2"),
+		`index(["a", "b"], 1)`:                  cty.StringVal("b"),
+		`length("nginx")`:                       cty.NumberIntVal(5),
+		`length(["a", "b"])`:                    cty.NumberIntVal(2),
+		`regex_replace("a1b2", "[0-9]", "")`:    cty.StringVal("ab"),
+		`strrev("abc")`:                         cty.StringVal("cba"),
+		`try(jsondecode("{"), "fallback")`:      cty.StringVal("fallback"),
+		`yamlencode({ a = 1 })`:                 cty.StringVal("\"a\": 1\n"),
+		`base64encode(sha1("nomad"))`:           cty.StringVal("NmRiOWMzNmRmMzlmYjhiN2I4MWJjYzg2OTRkNDhkMmMwY2NmOWNhOQ=="),
+		`convert(["a"], list(string))`:          cty.ListVal([]cty.Value{cty.StringVal("a")}),
+		`lookup({ a = "x" }, "b", "default")`:   cty.StringVal("default"),
+		`can(abs("ten"))`:                       cty.False,
+		`coalesce("", "b")`:                     cty.StringVal("b"),
+		`reverse([1, 2])`:                       cty.TupleVal([]cty.Value{cty.NumberIntVal(2), cty.NumberIntVal(1)}),
+		`fileexists("implementations_test.go")`: cty.True,
+	}
+
+	for src, expected := range tests {
+		expr, diags := hclsyntax.ParseExpression([]byte(src), "test.hcl", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+
+		val, diags := expr.Value(ctx)
+		if diags.HasErrors() {
+			t.Errorf("%s: %s", src, diags)
+			continue
+		}
+
+		if !val.RawEquals(expected) {
+			t.Errorf("%s: expected %#v, recieved: %#v", src, expected, val)
+		}
+	}
+}
+
+func TestPureImplementations(t *testing.T) {
+	ctx := &hcl.EvalContext{Functions: funcs.PureImplementations(".")}
+
+	for _, src := range []string{`bcrypt("secret")`, `file("missing.txt")`, `fileexists("implementations.go")`, `uuidv4()`} {
+		expr, diags := hclsyntax.ParseExpression([]byte(src), "test.hcl", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+
+		val, diags := expr.Value(ctx)
+		if diags.HasErrors() || val.IsKnown() {
+			t.Errorf("expected %s to be unknown, recieved: %#v %v", src, val, diags)
+		}
+	}
+
+	expr, diags := hclsyntax.ParseExpression([]byte(`file(["a"])`), "test.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if _, diags := expr.Value(ctx); !diags.HasErrors() {
+		t.Error("expected arguments of impure functions to be checked")
+	}
+}
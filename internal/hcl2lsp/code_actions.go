@@ -0,0 +1,35 @@
+package hcl2lsp
+
+import (
+	"go.lsp.dev/protocol"
+
+	"github.com/loczek/nomad-ls/internal/codeactions"
+)
+
+func CodeActions(fixes []codeactions.QuickFix) []protocol.CodeAction {
+	actions := make([]protocol.CodeAction, 0)
+
+	for _, fix := range fixes {
+		changes := make(map[protocol.DocumentURI][]protocol.TextEdit)
+
+		for _, edit := range fix.Edits {
+			uri := URI(edit.Range.Filename)
+			changes[uri] = append(changes[uri], protocol.TextEdit{
+				Range:   Range(edit.Range),
+				NewText: edit.NewText,
+			})
+		}
+
+		actions = append(actions, protocol.CodeAction{
+			Title:       fix.Title,
+			Kind:        protocol.QuickFix,
+			Diagnostics: Diagnostics(fix.Diagnostics),
+			IsPreferred: len(fixes) == 1,
+			Edit: &protocol.WorkspaceEdit{
+				Changes: changes,
+			},
+		})
+	}
+
+	return actions
+}
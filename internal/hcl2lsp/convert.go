@@ -2,6 +2,7 @@
 package hcl2lsp
 
 import (
+	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl/v2"
 	"go.lsp.dev/protocol"
@@ -110,8 +111,60 @@ func Diagnostics(diag hcl.Diagnostics) []protocol.Diagnostic {
 			newDiag.Message = v.Detail
 		}
 
+		switch v.Severity {
+		case hcl.DiagError:
+			newDiag.Severity = protocol.DiagnosticSeverityError
+		case hcl.DiagWarning:
+			newDiag.Severity = protocol.DiagnosticSeverityWarning
+		}
+
 		protocolDiagnostics = append(protocolDiagnostics, newDiag)
 	}
 
 	return protocolDiagnostics
 }
+
+func Range(rng hcl.Range) protocol.Range {
+	return protocol.Range{
+		Start: protocol.Position{
+			Line:      uint32(rng.Start.Line - 1),
+			Character: uint32(rng.Start.Column - 1),
+		},
+		End: protocol.Position{
+			Line:      uint32(rng.End.Line - 1),
+			Character: uint32(rng.End.Column - 1),
+		},
+	}
+}
+
+func Location(rng hcl.Range) protocol.Location {
+	return protocol.Location{
+		URI:   URI(rng.Filename),
+		Range: Range(rng),
+	}
+}
+
+func Definitions(targets decoder.ReferenceTargets) []protocol.Location {
+	locations := make([]protocol.Location, 0)
+
+	for _, target := range targets {
+		rng := target.Range
+		if target.DefRangePtr != nil {
+			rng = *target.DefRangePtr
+		}
+
+		locations = append(locations, Location(rng))
+	}
+
+	return locations
+}
+
+func References(origins decoder.ReferenceOrigins) []protocol.Location {
+	locations := make([]protocol.Location, 0)
+
+	for _, origin := range origins {
+		locations = append(locations, Location(origin.Range))
+	}
+
+	return locations
+}
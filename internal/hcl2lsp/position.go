@@ -1,12 +1,15 @@
 package hcl2lsp
 
 import (
+	"unicode/utf16"
 	"unicode/utf8"
 
 	"github.com/hashicorp/hcl/v2"
 	"go.lsp.dev/protocol"
 )
 
+// Position converts LSP position, where character is an offset
+// in UTF-16 code units, to hcl position with a byte offset into src
 func Position(pos protocol.Position, src []byte) hcl.Pos {
 	runes := []rune(string(src))
 
@@ -23,16 +26,43 @@ func Position(pos protocol.Position, src []byte) hcl.Pos {
 	}
 
 	var j uint
+	var column uint
 
-	for j < uint(pos.Character) && runeIndex < uint(len(runes)) {
+	// positions past the end of the line are clamped to it
+	for j < uint(pos.Character) && runeIndex < uint(len(runes)) && runes[runeIndex] != '\n' {
 		bytesCount += uint(utf8.RuneLen(runes[runeIndex]))
+		j += uint(utf16.RuneLen(runes[runeIndex]))
+		column += 1
 		runeIndex += 1
-		j += 1
 	}
 
 	return hcl.Pos{
-		Line:   int(pos.Line) + 1,
-		Column: int(pos.Character) + 1,
+		Line:   int(line) + 1,
+		Column: int(column) + 1,
 		Byte:   int(bytesCount),
 	}
 }
+
+// ApplyChange replaces the text within rng with text and returns the new content
+func ApplyChange(src []byte, rng protocol.Range, text string) []byte {
+	start, end := Offsets(rng, src)
+
+	out := make([]byte, 0, len(src)-(end-start)+len(text))
+	out = append(out, src[:start]...)
+	out = append(out, text...)
+	out = append(out, src[end:]...)
+
+	return out
+}
+
+// Offsets returns byte offsets of the start and the end of rng within src
+func Offsets(rng protocol.Range, src []byte) (start, end int) {
+	start = Position(rng.Start, src).Byte
+	end = Position(rng.End, src).Byte
+
+	if end < start {
+		start, end = end, start
+	}
+
+	return start, end
+}
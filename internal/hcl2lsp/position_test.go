@@ -17,11 +17,41 @@ const (
 func TestConvertProtocolPosition(t *testing.T) {
 	hclFile := LoadSampleFile(GENERIC_NOMAD_FILE_PATH)
 
-	actuallPos := hcl.Pos{Line: 14, Column: 3, Byte: 182}
-	predictedPos := Position(protocol.Position{Line: 13, Character: 2}, hclFile.Bytes)
+	tests := []struct {
+		name     string
+		pos      protocol.Position
+		expected hcl.Pos
+	}{
+		{
+			name:     "within a line",
+			pos:      protocol.Position{Line: 13, Character: 2},
+			expected: hcl.Pos{Line: 14, Column: 3, Byte: 169},
+		},
+		{
+			name:     "past the end of a line",
+			pos:      protocol.Position{Line: 13, Character: 40},
+			expected: hcl.Pos{Line: 14, Column: 16, Byte: 182},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			predictedPos := Position(tt.pos, hclFile.Bytes)
+
+			if tt.expected != predictedPos {
+				t.Errorf("expected: %v, recieved: %v", tt.expected, predictedPos)
+			}
+		})
+	}
+}
+
+func TestConvertProtocolPositionSurrogatePair(t *testing.T) {
+	// the emoji is 2 UTF-16 code units, 4 bytes and a single column
+	predictedPos := Position(protocol.Position{Line: 0, Character: 7}, []byte("a = \"😀x\"\n"))
 
-	if actuallPos != predictedPos {
-		t.Errorf("expected: %v, recieved: %v", actuallPos, predictedPos)
+	expected := hcl.Pos{Line: 1, Column: 7, Byte: 9}
+	if expected != predictedPos {
+		t.Errorf("expected: %v, recieved: %v", expected, predictedPos)
 	}
 }
 
@@ -35,5 +65,63 @@ func LoadSampleFile(path string) *hcl.File {
 
 	doc.ParseHCL(file, "name")
 
-	return doc.HCLFile
+	return doc.File()
+}
+
+func TestApplyChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		rng      protocol.Range
+		text     string
+		expected string
+	}{
+		{
+			name:     "insert at start",
+			src:      "job \"a\" {}\n",
+			rng:      protocol.Range{},
+			text:     "# comment\n",
+			expected: "# comment\njob \"a\" {}\n",
+		},
+		{
+			name: "replace label",
+			src:  "job \"a\" {\n  type = \"batch\"\n}\n",
+			rng: protocol.Range{
+				Start: protocol.Position{Line: 1, Character: 10},
+				End:   protocol.Position{Line: 1, Character: 15},
+			},
+			text:     "service",
+			expected: "job \"a\" {\n  type = \"service\"\n}\n",
+		},
+		{
+			name: "delete across lines",
+			src:  "a = 1\nb = 2\nc = 3\n",
+			rng: protocol.Range{
+				Start: protocol.Position{Line: 0, Character: 5},
+				End:   protocol.Position{Line: 1, Character: 5},
+			},
+			text:     "",
+			expected: "a = 1\nc = 3\n",
+		},
+		{
+			name: "utf-16 surrogate pair",
+			src:  "a = \"😀x\"\n",
+			rng: protocol.Range{
+				Start: protocol.Position{Line: 0, Character: 7},
+				End:   protocol.Position{Line: 0, Character: 8},
+			},
+			text:     "y",
+			expected: "a = \"😀y\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := ApplyChange([]byte(tt.src), tt.rng, tt.text)
+
+			if string(out) != tt.expected {
+				t.Errorf("expected: %q, recieved: %q", tt.expected, string(out))
+			}
+		})
+	}
 }
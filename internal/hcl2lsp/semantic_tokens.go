@@ -0,0 +1,150 @@
+package hcl2lsp
+
+import (
+	"bytes"
+	"unicode/utf16"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"go.lsp.dev/protocol"
+)
+
+// TokenTypes is the legend of token types, the index of each type
+// is used to encode tokens
+var TokenTypes = []protocol.SemanticTokenTypes{
+	protocol.SemanticTokenProperty,
+	protocol.SemanticTokenType,
+	protocol.SemanticTokenEnumMember,
+	protocol.SemanticTokenKeyword,
+	protocol.SemanticTokenString,
+	protocol.SemanticTokenNumber,
+	protocol.SemanticTokenParameter,
+	protocol.SemanticTokenVariable,
+	protocol.SemanticTokenFunction,
+}
+
+// hcl-lang token types are mapped onto the standard ones
+// so that they are highlighted by default themes
+var tokenTypeMap = map[lang.SemanticTokenType]protocol.SemanticTokenTypes{
+	lang.TokenAttrName:      protocol.SemanticTokenProperty,
+	lang.TokenBlockType:     protocol.SemanticTokenType,
+	lang.TokenBlockLabel:    protocol.SemanticTokenEnumMember,
+	lang.TokenBool:          protocol.SemanticTokenKeyword,
+	lang.TokenString:        protocol.SemanticTokenString,
+	lang.TokenNumber:        protocol.SemanticTokenNumber,
+	lang.TokenObjectKey:     protocol.SemanticTokenParameter,
+	lang.TokenMapKey:        protocol.SemanticTokenParameter,
+	lang.TokenKeyword:       protocol.SemanticTokenKeyword,
+	lang.TokenReferenceStep: protocol.SemanticTokenVariable,
+	lang.TokenTypeComplex:   protocol.SemanticTokenKeyword,
+	lang.TokenTypePrimitive: protocol.SemanticTokenKeyword,
+	lang.TokenFunctionName:  protocol.SemanticTokenFunction,
+}
+
+func SemanticTokensLegend() protocol.SemanticTokensLegend {
+	return protocol.SemanticTokensLegend{
+		TokenTypes:     TokenTypes,
+		TokenModifiers: []protocol.SemanticTokenModifiers{},
+	}
+}
+
+// SemanticTokens encodes tokens in the relative format,
+// tokens spanning multiple lines are split into one token per line
+func SemanticTokens(tokens []lang.SemanticToken, src []byte) []uint32 {
+	data := make([]uint32, 0)
+
+	var prevLine, prevChar uint32
+
+	for _, token := range tokens {
+		tokenType, ok := tokenTypeIndex(token.Type)
+		if !ok {
+			continue
+		}
+
+		for _, rng := range splitLines(token.Range, src) {
+			line := uint32(rng.Start.Line - 1)
+			char := utf16Len(src[lineStart(src, rng.Start.Byte):rng.Start.Byte])
+			length := utf16Len(src[rng.Start.Byte:rng.End.Byte])
+
+			if length == 0 {
+				continue
+			}
+
+			deltaLine := line - prevLine
+			deltaChar := char
+			if deltaLine == 0 {
+				deltaChar = char - prevChar
+			}
+
+			data = append(data, deltaLine, deltaChar, length, tokenType, 0)
+
+			prevLine = line
+			prevChar = char
+		}
+	}
+
+	return data
+}
+
+// SemanticTokensInRange filters tokens overlapping rng
+func SemanticTokensInRange(tokens []lang.SemanticToken, rng protocol.Range, src []byte) []lang.SemanticToken {
+	start := Position(rng.Start, src).Byte
+	end := Position(rng.End, src).Byte
+
+	filtered := make([]lang.SemanticToken, 0)
+	for _, token := range tokens {
+		if token.Range.End.Byte > start && token.Range.Start.Byte < end {
+			filtered = append(filtered, token)
+		}
+	}
+
+	return filtered
+}
+
+func tokenTypeIndex(tokenType lang.SemanticTokenType) (uint32, bool) {
+	lspType, ok := tokenTypeMap[tokenType]
+	if !ok {
+		return 0, false
+	}
+
+	for i, t := range TokenTypes {
+		if t == lspType {
+			return uint32(i), true
+		}
+	}
+
+	return 0, false
+}
+
+func splitLines(rng hcl.Range, src []byte) []hcl.Range {
+	if rng.End.Byte > len(src) || rng.Start.Byte > rng.End.Byte {
+		return nil
+	}
+
+	ranges := make([]hcl.Range, 0)
+
+	start := rng.Start
+	for {
+		idx := bytes.IndexByte(src[start.Byte:rng.End.Byte], '\n')
+		if idx == -1 {
+			ranges = append(ranges, hcl.Range{Filename: rng.Filename, Start: start, End: rng.End})
+			break
+		}
+
+		end := start
+		end.Byte += idx
+		ranges = append(ranges, hcl.Range{Filename: rng.Filename, Start: start, End: end})
+
+		start = hcl.Pos{Line: start.Line + 1, Column: 1, Byte: end.Byte + 1}
+	}
+
+	return ranges
+}
+
+func lineStart(src []byte, offset int) int {
+	return bytes.LastIndexByte(src[:offset], '\n') + 1
+}
+
+func utf16Len(src []byte) uint32 {
+	return uint32(len(utf16.Encode(bytes.Runes(src))))
+}
@@ -0,0 +1,46 @@
+package hcl2lsp
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestSemanticTokens(t *testing.T) {
+	src := []byte("job \"a\" {\n  meta = <<EOT\nfoo\nEOT\n}\n")
+
+	tokens := []lang.SemanticToken{
+		{
+			Type:  lang.TokenBlockType,
+			Range: hcl.Range{Start: hcl.Pos{Line: 1, Column: 1, Byte: 0}, End: hcl.Pos{Line: 1, Column: 4, Byte: 3}},
+		},
+		{
+			Type:  lang.TokenBlockLabel,
+			Range: hcl.Range{Start: hcl.Pos{Line: 1, Column: 5, Byte: 4}, End: hcl.Pos{Line: 1, Column: 8, Byte: 7}},
+		},
+		{
+			Type:  lang.TokenAttrName,
+			Range: hcl.Range{Start: hcl.Pos{Line: 2, Column: 3, Byte: 12}, End: hcl.Pos{Line: 2, Column: 7, Byte: 16}},
+		},
+		{
+			// heredoc content spanning two lines
+			Type:  lang.TokenString,
+			Range: hcl.Range{Start: hcl.Pos{Line: 2, Column: 15, Byte: 24}, End: hcl.Pos{Line: 3, Column: 4, Byte: 28}},
+		},
+	}
+
+	expected := []uint32{
+		0, 0, 3, 1, 0,
+		0, 4, 3, 2, 0,
+		1, 2, 4, 0, 0,
+		1, 0, 3, 4, 0,
+	}
+
+	data := SemanticTokens(tokens, src)
+
+	if !slices.Equal(expected, data) {
+		t.Errorf("expected: %v, recieved: %v", expected, data)
+	}
+}
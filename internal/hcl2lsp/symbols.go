@@ -0,0 +1,179 @@
+package hcl2lsp
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"go.lsp.dev/protocol"
+)
+
+var blockSymbolKinds = map[string]protocol.SymbolKind{
+	"job":       protocol.SymbolKindModule,
+	"group":     protocol.SymbolKindNamespace,
+	"task":      protocol.SymbolKindClass,
+	"service":   protocol.SymbolKindInterface,
+	"template":  protocol.SymbolKindFile,
+	"volume":    protocol.SymbolKindStruct,
+	"variable":  protocol.SymbolKindVariable,
+	"variables": protocol.SymbolKindNamespace,
+	"locals":    protocol.SymbolKindNamespace,
+}
+
+// blocks which are searchable across the workspace
+var workspaceSymbolBlocks = map[string]bool{
+	"job":      true,
+	"group":    true,
+	"task":     true,
+	"service":  true,
+	"variable": true,
+}
+
+// blocks whose attributes are declarations and belong in the outline
+var declarationBlocks = map[string]bool{
+	"locals":    true,
+	"variables": true,
+}
+
+func DocumentSymbols(symbols []decoder.Symbol) []protocol.DocumentSymbol {
+	return documentSymbols(symbols, "")
+}
+
+func documentSymbols(symbols []decoder.Symbol, parentType string) []protocol.DocumentSymbol {
+	docSymbols := make([]protocol.DocumentSymbol, 0)
+
+	for _, symbol := range symbols {
+		switch s := symbol.(type) {
+		case *decoder.BlockSymbol:
+			docSymbols = append(docSymbols, protocol.DocumentSymbol{
+				Name:           blockSymbolName(s),
+				Detail:         s.Type,
+				Kind:           blockSymbolKind(s),
+				Range:          Range(s.Range()),
+				SelectionRange: Range(selectionRange(s.Range())),
+				Children:       documentSymbols(s.NestedSymbols(), s.Type),
+			})
+		case *decoder.AttributeSymbol:
+			if !declarationBlocks[parentType] {
+				continue
+			}
+
+			docSymbols = append(docSymbols, protocol.DocumentSymbol{
+				Name:           s.Name(),
+				Detail:         parentType,
+				Kind:           protocol.SymbolKindVariable,
+				Range:          Range(s.Range()),
+				SelectionRange: Range(selectionRange(s.Range())),
+			})
+		}
+	}
+
+	return docSymbols
+}
+
+// WorkspaceSymbols flattens symbols of a file into searchable blocks
+// whose names contain the query, ignoring case
+func WorkspaceSymbols(symbols []decoder.Symbol, query string, src []byte) []protocol.SymbolInformation {
+	return workspaceSymbols(symbols, strings.ToLower(query), src, "")
+}
+
+func workspaceSymbols(symbols []decoder.Symbol, query string, src []byte, containerName string) []protocol.SymbolInformation {
+	infos := make([]protocol.SymbolInformation, 0)
+
+	for _, symbol := range symbols {
+		s, ok := symbol.(*decoder.BlockSymbol)
+		if !ok {
+			continue
+		}
+
+		name := blockSymbolName(s)
+		if len(s.Labels) == 0 {
+			if staticName, ok := nameAttributeValue(s, src); ok {
+				name = staticName
+			}
+		}
+
+		if workspaceSymbolBlocks[s.Type] && strings.Contains(strings.ToLower(name), query) {
+			infos = append(infos, protocol.SymbolInformation{
+				Name:          name,
+				Kind:          blockSymbolKind(s),
+				Location:      Location(s.Range()),
+				ContainerName: containerName,
+			})
+		}
+
+		nestedContainerName := name
+		if containerName != "" {
+			nestedContainerName = containerName + "." + name
+		}
+
+		infos = append(infos, workspaceSymbols(s.NestedSymbols(), query, src, nestedContainerName)...)
+	}
+
+	return infos
+}
+
+// nameAttributeValue returns static value of the `name` attribute,
+// used to name blocks without labels (e.g. `service`)
+func nameAttributeValue(symbol *decoder.BlockSymbol, src []byte) (string, bool) {
+	for _, nested := range symbol.NestedSymbols() {
+		attr, ok := nested.(*decoder.AttributeSymbol)
+		if !ok || attr.Name() != "name" {
+			continue
+		}
+
+		rng := attr.Range()
+		if rng.End.Byte > len(src) {
+			return "", false
+		}
+
+		file, diags := hclsyntax.ParseConfig(rng.SliceBytes(src), rng.Filename, rng.Start)
+		if diags.HasErrors() {
+			return "", false
+		}
+
+		parsed, ok := file.Body.(*hclsyntax.Body).Attributes["name"]
+		if !ok {
+			return "", false
+		}
+
+		val, diags := parsed.Expr.Value(nil)
+		if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || val.Type() != cty.String {
+			return "", false
+		}
+
+		return val.AsString(), true
+	}
+
+	return "", false
+}
+
+// blockSymbolName returns labels of the block (e.g. `api` for `group "api"`)
+// or block type for blocks without labels
+func blockSymbolName(symbol *decoder.BlockSymbol) string {
+	if len(symbol.Labels) == 0 {
+		return symbol.Type
+	}
+
+	return strings.Join(symbol.Labels, ".")
+}
+
+func blockSymbolKind(symbol *decoder.BlockSymbol) protocol.SymbolKind {
+	if kind, ok := blockSymbolKinds[symbol.Type]; ok {
+		return kind
+	}
+
+	return protocol.SymbolKindObject
+}
+
+// selectionRange returns an empty range at the start of the symbol
+// as symbols do not carry the range of their name
+func selectionRange(rng hcl.Range) hcl.Range {
+	return hcl.Range{
+		Filename: rng.Filename,
+		Start:    rng.Start,
+		End:      rng.Start,
+	}
+}
@@ -0,0 +1,115 @@
+package hcl2lsp
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/store"
+)
+
+func TestDocumentSymbols(t *testing.T) {
+	src, err := os.ReadFile(GENERIC_NOMAD_FILE_PATH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := store.NewStore()
+	doc := store.NewDocument(languages.NomadJob)
+	doc.ParseHCL(src, GENERIC_NOMAD_FILE_PATH)
+	s.AddFile(GENERIC_NOMAD_FILE_PATH, doc)
+
+	pathDec, err := decoder.NewDecoder(&s).Path(lang.Path{
+		Path:       GENERIC_NOMAD_FILE_PATH,
+		LanguageID: languages.NomadJob.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	symbols, err := pathDec.SymbolsInFile(GENERIC_NOMAD_FILE_PATH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docSymbols := DocumentSymbols(symbols)
+
+	if len(docSymbols) != 2 {
+		t.Fatalf("expected 2 top-level symbols, recieved: %d", len(docSymbols))
+	}
+
+	variables := docSymbols[0]
+	if variables.Name != "variables" || len(variables.Children) != 2 {
+		t.Errorf("expected variables with 2 attributes, recieved: %+v", variables)
+	}
+
+	job := docSymbols[1]
+	if job.Name != "example" || job.Detail != "job" {
+		t.Fatalf("expected job \"example\", recieved: %+v", job)
+	}
+
+	var group, task string
+	for _, child := range job.Children {
+		if child.Detail == "group" {
+			group = child.Name
+			for _, grandchild := range child.Children {
+				if grandchild.Detail == "task" {
+					task = grandchild.Name
+				}
+			}
+		}
+	}
+
+	if group != "app" || task != "server" {
+		t.Errorf("expected group \"app\" and task \"server\", recieved: %q and %q", group, task)
+	}
+}
+
+func TestWorkspaceSymbols(t *testing.T) {
+	src := []byte(`job "shop" {
+  group "api" {
+    task "server" {
+      driver = "docker"
+
+      service {
+        name = "api-http"
+      }
+    }
+  }
+}
+`)
+
+	s := store.NewStore()
+	doc := store.NewDocument(languages.NomadJob)
+	doc.ParseHCL(src, "shop.nomad.hcl")
+	s.AddFile("shop.nomad.hcl", doc)
+
+	pathDec, err := decoder.NewDecoder(&s).Path(lang.Path{
+		Path:       "shop.nomad.hcl",
+		LanguageID: languages.NomadJob.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	symbols, err := pathDec.SymbolsInFile("shop.nomad.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	infos := WorkspaceSymbols(symbols, "API", src)
+
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 symbols, recieved: %+v", infos)
+	}
+
+	if infos[0].Name != "api" || infos[0].ContainerName != "shop" {
+		t.Errorf("expected group \"api\" in \"shop\", recieved: %+v", infos[0])
+	}
+
+	if infos[1].Name != "api-http" || infos[1].ContainerName != "shop.api.server" {
+		t.Errorf("expected service \"api-http\" in \"shop.api.server\", recieved: %+v", infos[1])
+	}
+}
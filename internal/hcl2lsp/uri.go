@@ -1,6 +1,9 @@
 package hcl2lsp
 
-import "go.lsp.dev/protocol"
+import (
+	"go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
+)
 
 func FileName(params protocol.TextDocumentIdentifier) string {
 	return params.URI.Filename()
@@ -13,3 +16,7 @@ func FileNameVersioned(params protocol.VersionedTextDocumentIdentifier) string {
 func FileNameItem(params protocol.TextDocumentItem) string {
 	return params.URI.Filename()
 }
+
+func URI(fileName string) protocol.DocumentURI {
+	return uri.File(fileName)
+}
@@ -12,6 +12,7 @@ const (
 	NomadNodePool          LanguageID = "nomad-node-pool"
 	NomadResourceQuota     LanguageID = "nomad-resource-quota"
 	NomadVariable          LanguageID = "nomad-variable"
+	NomadVars              LanguageID = "nomad-vars"
 )
 
 var langs = map[string]LanguageID{
@@ -24,6 +25,7 @@ var langs = map[string]LanguageID{
 	"nomad-node-pool":           NomadNodePool,
 	"nomad-resource-quota":      NomadResourceQuota,
 	"nomad-variable":            NomadVariable,
+	"nomad-vars":                NomadVars,
 }
 
 func (l LanguageID) String() string {
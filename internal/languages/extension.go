@@ -0,0 +1,33 @@
+package languages
+
+import "strings"
+
+// extensions maps file name suffixes to languages,
+// longer suffixes have to be checked before shorter ones
+var extensions = []struct {
+	suffix string
+	lang   LanguageID
+}{
+	{".vars.hcl", NomadVars},
+	{".nomad.hcl", NomadJob},
+	{".nomad.acl", NomadACL},
+	{".nomad.agent", NomadAgent},
+	{".nomad.csi", NomadCSIVolume},
+	{".nomad.dyn", NomadDynamicHostVolume},
+	{".nomad.ns", NomadNapespace},
+	{".nomad.np", NomadNodePool},
+	{".nomad.rq", NomadResourceQuota},
+	{".nomad.var", NomadVariable},
+	{".nomad", NomadJob},
+}
+
+// FromFileName returns the language of a file based on its extension
+func FromFileName(name string) (LanguageID, bool) {
+	for _, ext := range extensions {
+		if strings.HasSuffix(name, ext.suffix) {
+			return ext.lang, true
+		}
+	}
+
+	return "", false
+}
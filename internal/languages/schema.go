@@ -15,6 +15,7 @@ var schemaMap = map[LanguageID]*hclSchema.BodySchema{
 	NomadNodePool:          schema.NomadNodePool,
 	NomadResourceQuota:     schema.NomadResourceQuota,
 	NomadVariable:          schema.NomadVariable,
+	NomadVars:              schema.NomadVars,
 }
 
 func ToSchema(lang LanguageID) hclSchema.BodySchema {
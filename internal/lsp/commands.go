@@ -0,0 +1,69 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.lsp.dev/protocol"
+
+	"github.com/loczek/nomad-ls/internal/hcl2lsp"
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/render"
+)
+
+// commandRenderJob renders a job into the JSON structure of the Nomad API,
+// arguments are the URI of the job file and optional [render.Options]
+const commandRenderJob = "nomad-ls.renderJob"
+
+var commands = []string{commandRenderJob}
+
+func (s *Service) HandleWorkspaceExecuteCommand(ctx context.Context, params *protocol.ExecuteCommandParams) (any, error) {
+	switch params.Command {
+	case commandRenderJob:
+		return s.renderJob(params.Arguments)
+	default:
+		return nil, fmt.Errorf("unknown command: %s", params.Command)
+	}
+}
+
+// renderJob renders the job from its content in the editor, unsaved changes included
+func (s *Service) renderJob(args []any) (json.RawMessage, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s expects the URI of a job file", commandRenderJob)
+	}
+
+	docURI, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("%s expects the URI of a job file, recieved: %v", commandRenderJob, args[0])
+	}
+
+	opts := render.Options{}
+	if len(args) > 1 {
+		raw, err := json.Marshal(args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(raw, &opts); err != nil {
+			return nil, fmt.Errorf("invalid options of %s: %w", commandRenderJob, err)
+		}
+	}
+
+	fileName := hcl2lsp.FileName(protocol.TextDocumentIdentifier{URI: protocol.DocumentURI(docURI)})
+	file, err := s.store.GetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Language != languages.NomadJob {
+		return nil, fmt.Errorf("%s is not a job file", fileName)
+	}
+
+	job, diags := render.Job(fileName, file.File().Bytes, opts)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return render.JSON(job)
+}
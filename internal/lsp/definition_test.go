@@ -0,0 +1,69 @@
+package lsp
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"go.lsp.dev/protocol"
+)
+
+const (
+	REFERENCES_JOB_FILE_PATH  = "./testdata/references/app.nomad.hcl"
+	REFERENCES_VARS_FILE_PATH = "./testdata/references/prod.vars.hcl"
+)
+
+func definition(t *testing.T, s *Service, uri protocol.DocumentURI, position protocol.Position) []protocol.Location {
+	locations, err := s.HandleTextDocumentDefinition(context.Background(), &protocol.DefinitionParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     position,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return locations
+}
+
+func TestDefinition(t *testing.T) {
+	s := New(nil, *slog.Default())
+	jobURI := openFile(t, &s, REFERENCES_JOB_FILE_PATH, "nomad-job")
+
+	tests := map[string]struct {
+		position protocol.Position
+		expected []protocol.Range
+	}{
+		"variable": {
+			position: protocol.Position{Line: 18, Character: 22},
+			expected: []protocol.Range{{Start: protocol.Position{Line: 0, Character: 0}, End: protocol.Position{Line: 0, Character: 16}}},
+		},
+		"local": {
+			position: protocol.Position{Line: 14, Character: 33},
+			expected: []protocol.Range{{Start: protocol.Position{Line: 5, Character: 2}, End: protocol.Position{Line: 5, Character: 5}}},
+		},
+		"undeclared variable": {
+			position: protocol.Position{Line: 19, Character: 22},
+		},
+		"outside of origins": {
+			position: protocol.Position{Line: 11, Character: 8},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			locations := definition(t, &s, jobURI, test.position)
+
+			if len(locations) != len(test.expected) {
+				t.Fatalf("expected %d locations, recieved: %+v", len(test.expected), locations)
+			}
+
+			for i, location := range locations {
+				if location.URI != jobURI || location.Range != test.expected[i] {
+					t.Errorf("expected %+v of the job, recieved: %+v", test.expected[i], location)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"go.lsp.dev/protocol"
+
+	"github.com/loczek/nomad-ls/internal/hcl2lsp"
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/store"
+)
+
+// LSP 3.17 methods missing from [protocol]
+const (
+	MethodTextDocumentDiagnostic     = "textDocument/diagnostic"
+	MethodWorkspaceDiagnostic        = "workspace/diagnostic"
+	MethodWorkspaceDiagnosticRefresh = "workspace/diagnostic/refresh"
+	diagnosticProviderIdentifier     = "nomad-ls"
+)
+
+// refreshDelay is the time changes are collected for before clients are asked
+// to pull diagnostics again
+const refreshDelay = 200 * time.Millisecond
+
+// fileDiagnostics returns syntax, reference and schema diagnostics of a file in the store
+func (s *Service) fileDiagnostics(ctx context.Context, fileName string, file *store.Document) (hcl.Diagnostics, error) {
+	// parse diagnostics are not kept in the store
+	_, diags := hclsyntax.ParseConfig(file.File().Bytes, fileName, hcl.InitialPos)
+
+	dec := decoder.NewDecoder(&s.store)
+	langPath := lang.Path{
+		Path:       fileName,
+		LanguageID: string(file.Language),
+	}
+
+	pathDec, err := dec.Path(langPath)
+	if err != nil {
+		return nil, err
+	}
+
+	validationDiags, err := s.validateFile(ctx, pathDec, langPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return diags.Extend(validationDiags), nil
+}
+
+// resultID identifies diagnostics of a file by its content and the content of files
+// it depends on, i.e. sibling jobs of var files, unchanged content yields unchanged diagnostics
+func (s *Service) resultID(fileName string, file *store.Document) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s:%x;", file.Language, contentHash(file))
+
+	// var files are checked against variables of sibling jobs, each one is hashed
+	// with its name so that moving content between them changes the result
+	if file.Language == languages.NomadVars {
+		for _, sibling := range s.store.Siblings(fileName, languages.NomadJob) {
+			if job, err := s.store.GetFile(sibling); err == nil {
+				fmt.Fprintf(h, "%s:%x;", sibling, contentHash(job))
+			}
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+func contentHash(file *store.Document) uint64 {
+	h := fnv.New64a()
+	h.Write(file.File().Bytes)
+
+	return h.Sum64()
+}
+
+// updateDependentDiagnostics updates diagnostics of var files checked against variables
+// of the changed job, they are published to clients which do not pull diagnostics
+func (s *Service) updateDependentDiagnostics(ctx context.Context, fileName string) {
+	// closed jobs outside of the workspace are removed from the store already
+	if langID, ok := languages.FromFileName(fileName); !ok || langID != languages.NomadJob {
+		return
+	}
+
+	varFiles := s.store.Siblings(fileName, languages.NomadVars)
+	if len(varFiles) == 0 {
+		return
+	}
+
+	if s.diagnosticCapabilities.PullSupport() {
+		s.refreshDiagnostics(ctx)
+		return
+	}
+
+	for _, path := range varFiles {
+		doc, err := s.store.GetFile(path)
+		if err != nil || !doc.IsOpen() {
+			continue
+		}
+
+		diags, err := s.fileDiagnostics(ctx, path, doc)
+		if err != nil {
+			s.logger.Error("failed to collect diagnostics", "path", path, "error", err.Error())
+			continue
+		}
+
+		s.con.Notify(ctx, protocol.MethodTextDocumentPublishDiagnostics, protocol.PublishDiagnosticsParams{
+			URI:         hcl2lsp.URI(path),
+			Version:     uint32(doc.Version()),
+			Diagnostics: hcl2lsp.Diagnostics(diags),
+		})
+	}
+}
+
+// refreshDiagnostics asks the client to pull diagnostics again, e.g. once files of the
+// workspace are indexed. Requests within [refreshDelay] are sent once without waiting for the client
+func (s *Service) refreshDiagnostics(ctx context.Context) {
+	if !s.diagnosticCapabilities.RefreshSupport() {
+		return
+	}
+
+	// the request outlives the handler which asked for it
+	ctx = context.WithoutCancel(ctx)
+
+	s.refresh.Do(func() {
+		if _, err := s.con.Call(ctx, MethodWorkspaceDiagnosticRefresh, nil, nil); err != nil {
+			s.logger.Error("failed to refresh diagnostics", "error", err.Error())
+		}
+	})
+}
+
+// debouncer runs only the last of the functions passed within its delay
+type debouncer struct {
+	mu    sync.Mutex
+	delay time.Duration
+	timer *time.Timer
+}
+
+func newDebouncer(delay time.Duration) *debouncer {
+	return &debouncer{delay: delay}
+}
+
+// Do runs f after the delay unless another function is passed in the meantime
+func (d *debouncer) Do(f func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timer = time.AfterFunc(d.delay, f)
+}
@@ -0,0 +1,228 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.lsp.dev/jsonrpc2"
+	"go.lsp.dev/protocol"
+
+	"github.com/loczek/nomad-ls/internal/hcl2lsp"
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/store"
+)
+
+const (
+	VARS_JOB_FILE_PATH = "./testdata/vars/app.nomad.hcl"
+	VARS_FILE_PATH     = "./testdata/vars/prod.vars.hcl"
+)
+
+// openFile opens the file in the service the same way as editors do
+func openFile(t *testing.T, s *Service, path string, langID string) protocol.DocumentURI {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uri := hcl2lsp.URI(path)
+
+	_, err = s.HandleTextDocumentDidOpen(context.Background(), &protocol.DidOpenTextDocumentParams{
+		TextDocument: protocol.TextDocumentItem{
+			URI:        uri,
+			LanguageID: protocol.LanguageIdentifier(langID),
+			Text:       string(src),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return uri
+}
+
+// renameVariable renames the variable declared by the job so that the var file assigns an undeclared one
+func renameVariable(t *testing.T, s *Service, jobURI protocol.DocumentURI) {
+	file, err := s.store.GetFile(jobURI.Filename())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.HandleTextDocumentDidChange(context.Background(), &DidChangeTextDocumentParams{
+		TextDocument: protocol.VersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: protocol.TextDocumentIdentifier{URI: jobURI},
+			Version:                2,
+		},
+		ContentChanges: []TextDocumentContentChangeEvent{
+			{Text: strings.ReplaceAll(string(file.File().Bytes), "replicas", "instances")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVarFileResultIDDependsOnJob(t *testing.T) {
+	s := New(nil, *slog.Default())
+
+	jobURI := openFile(t, &s, VARS_JOB_FILE_PATH, "nomad-job")
+	varsURI := openFile(t, &s, VARS_FILE_PATH, "nomad-vars")
+
+	varFile, err := s.store.GetFile(varsURI.Filename())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := s.resultID(varsURI.Filename(), varFile)
+
+	renameVariable(t, &s, jobURI)
+
+	if after := s.resultID(varsURI.Filename(), varFile); before == after {
+		t.Errorf("expected the result ID of the var file to change with its job, recieved: %s", after)
+	}
+}
+
+func TestVarFileResultIDDependsOnEachJob(t *testing.T) {
+	resultID := func(jobs map[string]string) string {
+		s := New(nil, *slog.Default())
+
+		for name, src := range jobs {
+			doc := store.NewDocument(languages.NomadJob)
+			doc.ParseHCL([]byte(src), "/jobs/"+name)
+			s.store.AddFile("/jobs/"+name, doc)
+		}
+
+		varFile := store.NewDocument(languages.NomadVars)
+		varFile.ParseHCL([]byte("replicas = 3\n"), "/jobs/prod.vars.hcl")
+		s.store.AddFile("/jobs/prod.vars.hcl", varFile)
+
+		return s.resultID("/jobs/prod.vars.hcl", varFile)
+	}
+
+	// the content of the jobs joined together is the same
+	before := resultID(map[string]string{"api.nomad.hcl": "a = 1\n", "web.nomad.hcl": "b = 2\n"})
+	after := resultID(map[string]string{"api.nomad.hcl": "a = 1\nb = 2\n", "web.nomad.hcl": ""})
+
+	if before == after {
+		t.Errorf("expected the result ID to change when content moves between jobs, recieved: %s", after)
+	}
+}
+
+func TestRefreshDiagnosticsIsDebounced(t *testing.T) {
+	serverPipe, clientPipe := net.Pipe()
+
+	var refreshes atomic.Int32
+
+	client := jsonrpc2.NewConn(jsonrpc2.NewStream(clientPipe))
+	client.Go(context.Background(), func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
+		if req.Method() == MethodWorkspaceDiagnosticRefresh {
+			refreshes.Add(1)
+		}
+
+		return reply(ctx, nil, nil)
+	})
+	defer client.Close()
+
+	server := jsonrpc2.NewConn(jsonrpc2.NewStream(serverPipe))
+	server.Go(context.Background(), jsonrpc2.MethodNotFoundHandler)
+	defer server.Close()
+
+	s := New(server, *slog.Default())
+	err := json.Unmarshal([]byte(`{"capabilities": {"workspace": {"diagnostics": {"refreshSupport": true}}}}`), &s.diagnosticCapabilities)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 10 {
+		s.refreshDiagnostics(context.Background())
+	}
+
+	time.Sleep(4 * refreshDelay)
+
+	if refreshes.Load() != 1 {
+		t.Errorf("expected changes to be refreshed once, recieved: %d", refreshes.Load())
+	}
+}
+
+func TestVarFileDiagnosticsArePublishedOnJobChange(t *testing.T) {
+	serverPipe, clientPipe := net.Pipe()
+
+	published := make(chan protocol.PublishDiagnosticsParams, 1)
+
+	client := jsonrpc2.NewConn(jsonrpc2.NewStream(clientPipe))
+	client.Go(context.Background(), func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
+		if req.Method() == protocol.MethodTextDocumentPublishDiagnostics {
+			params := protocol.PublishDiagnosticsParams{}
+			if err := json.Unmarshal(req.Params(), &params); err == nil {
+				published <- params
+			}
+		}
+
+		return reply(ctx, nil, nil)
+	})
+	defer client.Close()
+
+	server := jsonrpc2.NewConn(jsonrpc2.NewStream(serverPipe))
+	defer server.Close()
+
+	s := New(server, *slog.Default())
+
+	jobURI := openFile(t, &s, VARS_JOB_FILE_PATH, "nomad-job")
+	varsURI := openFile(t, &s, VARS_FILE_PATH, "nomad-vars")
+
+	renameVariable(t, &s, jobURI)
+	s.updateDependentDiagnostics(context.Background(), jobURI.Filename())
+
+	select {
+	case params := <-published:
+		if params.URI != varsURI {
+			t.Errorf("expected diagnostics of %s, recieved: %s", varsURI, params.URI)
+		}
+
+		if len(params.Diagnostics) == 0 {
+			t.Error("expected the undeclared variable to be reported")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected diagnostics of the var file to be published")
+	}
+}
+
+func TestDidOpenReportsUndeclaredReferences(t *testing.T) {
+	s := New(nil, *slog.Default())
+
+	path, err := filepath.Abs(VARS_JOB_FILE_PATH)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags, err := s.HandleTextDocumentDidOpen(context.Background(), &protocol.DidOpenTextDocumentParams{
+		TextDocument: protocol.TextDocumentItem{
+			URI:        hcl2lsp.URI(path),
+			LanguageID: "nomad-job",
+			Text:       "job \"app\" {\n  group \"web\" {\n    count = var.missing\n  }\n}\n",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, diag := range *diags {
+		if diag.Message == `No declaration found for "var.missing"` {
+			return
+		}
+	}
+
+	t.Errorf("expected the undeclared variable to be reported on open, recieved: %v", *diags)
+}
@@ -6,47 +6,124 @@ import (
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"go.lsp.dev/protocol"
+	"go.lsp.dev/uri"
 
+	"github.com/loczek/nomad-ls/internal/codeactions"
 	"github.com/loczek/nomad-ls/internal/hcl2lsp"
 	"github.com/loczek/nomad-ls/internal/languages"
 	"github.com/loczek/nomad-ls/internal/store"
 	"github.com/loczek/nomad-ls/internal/validation"
 )
 
-func (s *Service) HandleInitialize(ctx context.Context, params *protocol.InitializedParams) (*protocol.InitializeResult, error) {
+func (s *Service) HandleInitialize(ctx context.Context, params *protocol.InitializeParams) (*InitializeResult, error) {
 	info, ok := debug.ReadBuildInfo()
 	if !ok {
 		return nil, errors.New("could not read build info")
 	}
 
-	return &protocol.InitializeResult{
+	if len(params.WorkspaceFolders) > 0 {
+		for _, folder := range params.WorkspaceFolders {
+			s.workspace.AddFolder(uri.URI(folder.URI).Filename())
+		}
+	} else if params.RootURI != "" {
+		s.workspace.AddFolder(params.RootURI.Filename())
+	}
+
+	s.applyInitializationOptions(params.InitializationOptions)
+
+	return &InitializeResult{
 		ServerInfo: &protocol.ServerInfo{
 			Name:    "nomad-ls",
 			Version: strings.TrimPrefix(info.Main.Version, "v"),
 		},
-		Capabilities: protocol.ServerCapabilities{
-			CompletionProvider: &protocol.CompletionOptions{},
-			HoverProvider:      &protocol.HoverOptions{},
-			TextDocumentSync: &protocol.TextDocumentSyncOptions{
-				Change:    protocol.TextDocumentSyncKindFull,
-				OpenClose: true,
+		Capabilities: ServerCapabilities{
+			DiagnosticProvider: &DiagnosticOptions{
+				Identifier:            diagnosticProviderIdentifier,
+				InterFileDependencies: true,
+				WorkspaceDiagnostics:  true,
 			},
-			SignatureHelpProvider: &protocol.SignatureHelpOptions{
-				TriggerCharacters:   []string{"(", ","},
-				RetriggerCharacters: []string{")"},
+			ServerCapabilities: protocol.ServerCapabilities{
+				CompletionProvider: &protocol.CompletionOptions{},
+				HoverProvider:      &protocol.HoverOptions{},
+				TextDocumentSync: &protocol.TextDocumentSyncOptions{
+					Change:    protocol.TextDocumentSyncKindIncremental,
+					OpenClose: true,
+				},
+				SignatureHelpProvider: &protocol.SignatureHelpOptions{
+					TriggerCharacters:   []string{"(", ","},
+					RetriggerCharacters: []string{")"},
+				},
+				DocumentFormattingProvider: &protocol.DocumentFormattingOptions{},
+				DefinitionProvider:         &protocol.DefinitionOptions{},
+				ReferencesProvider:         &protocol.ReferenceOptions{},
+				DocumentSymbolProvider:     &protocol.DocumentSymbolOptions{},
+				WorkspaceSymbolProvider:    &protocol.WorkspaceSymbolOptions{},
+				CodeActionProvider: &protocol.CodeActionOptions{
+					CodeActionKinds: []protocol.CodeActionKind{protocol.QuickFix},
+				},
+				SemanticTokensProvider: &SemanticTokensOptions{
+					Legend: hcl2lsp.SemanticTokensLegend(),
+					Range:  true,
+					Full:   true,
+				},
+				RenameProvider: &protocol.RenameOptions{
+					PrepareProvider: true,
+				},
+				ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
+					Commands: commands,
+				},
+				Workspace: &protocol.ServerCapabilitiesWorkspace{
+					WorkspaceFolders: &protocol.ServerCapabilitiesWorkspaceFolders{
+						Supported:           true,
+						ChangeNotifications: true,
+					},
+				},
 			},
-			DocumentFormattingProvider: &protocol.DocumentFormattingOptions{},
 		},
 	}, nil
 }
 
+// HandleInitialized starts indexing of the workspace folders in the background
+func (s *Service) HandleInitialized(ctx context.Context, params *protocol.InitializedParams) error {
+	go func() {
+		s.workspace.IndexAll(context.Background())
+		s.refreshDiagnostics(context.Background())
+	}()
+
+	return nil
+}
+
+func (s *Service) HandleWorkspaceDidChangeWorkspaceFolders(ctx context.Context, params *protocol.DidChangeWorkspaceFoldersParams) error {
+	for _, folder := range params.Event.Removed {
+		s.workspace.RemoveFolder(uri.URI(folder.URI).Filename())
+	}
+
+	for _, folder := range params.Event.Added {
+		path := uri.URI(folder.URI).Filename()
+		s.workspace.AddFolder(path)
+
+		go func() {
+			if err := s.workspace.Index(context.Background(), path); err != nil {
+				s.logger.Error("failed to index workspace folder", "folder", path, "error", err.Error())
+			}
+
+			s.refreshDiagnostics(context.Background())
+		}()
+	}
+
+	return nil
+}
+
 func (s *Service) HandleTextDocumentHover(ctx context.Context, params *protocol.HoverParams) (*protocol.Hover, error) {
 	fileName := hcl2lsp.FileName(params.TextDocument)
 	file, err := s.store.GetFile(fileName)
@@ -54,7 +131,7 @@ func (s *Service) HandleTextDocumentHover(ctx context.Context, params *protocol.
 		return nil, err
 	}
 
-	pos := hcl2lsp.Position(params.Position, file.HCLFile.Bytes)
+	pos := hcl2lsp.Position(params.Position, file.File().Bytes)
 
 	dec := decoder.NewDecoder(&s.store)
 	langPath := lang.Path{
@@ -72,6 +149,19 @@ func (s *Service) HandleTextDocumentHover(ctx context.Context, params *protocol.
 		return nil, err
 	}
 
+	for _, hover := range []func(*store.Document, hcl.Pos) (string, bool){literalHover, valueHover} {
+		desc, ok := hover(file, pos)
+		if !ok {
+			continue
+		}
+
+		if hoverData == nil {
+			hoverData = &lang.HoverData{Content: lang.Markdown(desc)}
+		} else {
+			hoverData.Content.Value += "\n\n" + desc
+		}
+	}
+
 	if hoverData == nil {
 		return nil, nil
 	}
@@ -88,7 +178,7 @@ func (s *Service) HandleTextDocumentSignatureHelp(ctx context.Context, params *p
 		return nil, err
 	}
 
-	pos := hcl2lsp.Position(params.Position, file.HCLFile.Bytes)
+	pos := hcl2lsp.Position(params.Position, file.File().Bytes)
 
 	dec := decoder.NewDecoder(&s.store)
 	langPath := lang.Path{
@@ -122,7 +212,7 @@ func (s *Service) HandleTextDocumentCompletion(ctx context.Context, params *prot
 		return nil, err
 	}
 
-	pos := hcl2lsp.Position(params.Position, file.HCLFile.Bytes)
+	pos := hcl2lsp.Position(params.Position, file.File().Bytes)
 
 	dec := decoder.NewDecoder(&s.store)
 	langPath := lang.Path{
@@ -141,6 +231,8 @@ func (s *Service) HandleTextDocumentCompletion(ctx context.Context, params *prot
 	}
 
 	completions := hcl2lsp.Completions(cands)
+	completions = append(completions, labelCompletions(file, pos)...)
+	completions = append(completions, nodePropertyCompletions(file, s.store.NodeTargets(), pos)...)
 
 	return &protocol.CompletionList{
 		IsIncomplete: cands.IsComplete,
@@ -148,6 +240,314 @@ func (s *Service) HandleTextDocumentCompletion(ctx context.Context, params *prot
 	}, nil
 }
 
+func (s *Service) HandleTextDocumentDefinition(ctx context.Context, params *protocol.DefinitionParams) ([]protocol.Location, error) {
+	fileName := hcl2lsp.FileName(params.TextDocument)
+	file, err := s.store.GetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := hcl2lsp.Position(params.Position, file.File().Bytes)
+
+	dec := decoder.NewDecoder(&s.store)
+	langPath := lang.Path{
+		Path:       fileName,
+		LanguageID: string(file.Language),
+	}
+
+	targets, err := dec.ReferenceTargetsForOriginAtPos(langPath, fileName, pos)
+	if err != nil {
+		var noOriginErr *reference.NoOriginFound
+		if errors.As(err, &noOriginErr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return hcl2lsp.Definitions(targets), nil
+}
+
+func (s *Service) HandleTextDocumentReferences(ctx context.Context, params *protocol.ReferenceParams) ([]protocol.Location, error) {
+	fileName := hcl2lsp.FileName(params.TextDocument)
+	file, err := s.store.GetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := hcl2lsp.Position(params.Position, file.File().Bytes)
+
+	dec := decoder.NewDecoder(&s.store)
+	langPath := lang.Path{
+		Path:       fileName,
+		LanguageID: string(file.Language),
+	}
+
+	// when the cursor is on an origin (e.g. var.image) look up
+	// references of the target it points to instead
+	targets, err := dec.ReferenceTargetsForOriginAtPos(langPath, fileName, pos)
+	if err == nil && len(targets) > 0 {
+		langPath = targets[0].Path
+		fileName = targets[0].Range.Filename
+		pos = targets[0].Range.Start
+	}
+
+	origins := dec.ReferenceOriginsTargetingPos(langPath, fileName, pos)
+
+	locations := make([]protocol.Location, 0)
+
+	pathCtx, err := s.store.PathContext(langPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// a single block can produce multiple targets (e.g. as a reference and as a type)
+	seen := make(map[hcl.Range]bool)
+	assignments := make([]hcl.Range, 0)
+
+	declarations, _ := pathCtx.ReferenceTargets.InnermostAtPos(fileName, pos)
+	for _, target := range declarations {
+		if target.DefRangePtr == nil || seen[*target.DefRangePtr] {
+			continue
+		}
+		seen[*target.DefRangePtr] = true
+
+		if params.Context.IncludeDeclaration {
+			locations = append(locations, hcl2lsp.Location(*target.DefRangePtr))
+		}
+
+		// var files assign variables of the job by their names
+		assignments = append(assignments, s.varFileNameRanges(fileName, target.Addr)...)
+	}
+
+	locations = append(locations, hcl2lsp.References(origins)...)
+	for _, rng := range assignments {
+		locations = append(locations, hcl2lsp.Location(rng))
+	}
+
+	return locations, nil
+}
+
+func (s *Service) HandleTextDocumentPrepareRename(ctx context.Context, params *protocol.PrepareRenameParams) (*protocol.Range, error) {
+	fileName := hcl2lsp.FileName(params.TextDocument)
+	file, err := s.store.GetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := hcl2lsp.Position(params.Position, file.File().Bytes)
+
+	dec := decoder.NewDecoder(&s.store)
+	langPath := lang.Path{
+		Path:       fileName,
+		LanguageID: string(file.Language),
+	}
+
+	_, nameRange, err := s.findRenameTarget(dec, langPath, fileName, pos)
+	if err != nil {
+		if errors.Is(err, errNotRenameable) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rng := hcl2lsp.Range(nameRange)
+
+	return &rng, nil
+}
+
+func (s *Service) HandleTextDocumentRename(ctx context.Context, params *protocol.RenameParams) (*protocol.WorkspaceEdit, error) {
+	if !hclsyntax.ValidIdentifier(params.NewName) {
+		return nil, fmt.Errorf("%q is not a valid identifier", params.NewName)
+	}
+
+	fileName := hcl2lsp.FileName(params.TextDocument)
+	file, err := s.store.GetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := hcl2lsp.Position(params.Position, file.File().Bytes)
+
+	dec := decoder.NewDecoder(&s.store)
+	langPath := lang.Path{
+		Path:       fileName,
+		LanguageID: string(file.Language),
+	}
+
+	declaration, _, err := s.findRenameTarget(dec, langPath, fileName, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	if declaration.name() != params.NewName {
+		declared, err := s.isDeclared(declaration, params.NewName)
+		if err != nil {
+			return nil, err
+		}
+
+		if declared {
+			return nil, fmt.Errorf("%s.%s is already declared", declaration.target.Addr[0].String(), params.NewName)
+		}
+	}
+
+	changes := make(map[protocol.DocumentURI][]protocol.TextEdit)
+
+	declURI := hcl2lsp.URI(declaration.nameRange.Filename)
+	changes[declURI] = append(changes[declURI], protocol.TextEdit{
+		Range:   hcl2lsp.Range(declaration.nameRange),
+		NewText: params.NewName,
+	})
+
+	declRange := *declaration.target.DefRangePtr
+	origins := dec.ReferenceOriginsTargetingPos(declaration.path, declRange.Filename, declRange.Start)
+
+	for _, origin := range origins {
+		originFile, err := s.store.GetFile(origin.Range.Filename)
+		if err != nil {
+			continue
+		}
+
+		nameRange, ok := originNameRange(originFile.File().Bytes, origin.Range, declaration.target.Addr)
+		if !ok {
+			continue
+		}
+
+		uri := hcl2lsp.URI(origin.Range.Filename)
+		changes[uri] = append(changes[uri], protocol.TextEdit{
+			Range:   hcl2lsp.Range(nameRange),
+			NewText: params.NewName,
+		})
+	}
+
+	for _, nameRange := range s.varFileNameRanges(declaration.nameRange.Filename, declaration.target.Addr) {
+		uri := hcl2lsp.URI(nameRange.Filename)
+		changes[uri] = append(changes[uri], protocol.TextEdit{
+			Range:   hcl2lsp.Range(nameRange),
+			NewText: params.NewName,
+		})
+	}
+
+	return &protocol.WorkspaceEdit{
+		Changes: changes,
+	}, nil
+}
+
+func (s *Service) HandleTextDocumentDocumentSymbol(ctx context.Context, params *protocol.DocumentSymbolParams) ([]protocol.DocumentSymbol, error) {
+	fileName := hcl2lsp.FileName(params.TextDocument)
+	file, err := s.store.GetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := decoder.NewDecoder(&s.store)
+	langPath := lang.Path{
+		Path:       fileName,
+		LanguageID: string(file.Language),
+	}
+
+	pathDec, err := dec.Path(langPath)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols, err := pathDec.SymbolsInFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return hcl2lsp.DocumentSymbols(symbols), nil
+}
+
+func (s *Service) HandleWorkspaceSymbol(ctx context.Context, params *protocol.WorkspaceSymbolParams) ([]protocol.SymbolInformation, error) {
+	dec := decoder.NewDecoder(&s.store)
+
+	paths := s.store.Paths(ctx)
+	slices.SortFunc(paths, func(a, b lang.Path) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	infos := make([]protocol.SymbolInformation, 0)
+
+	for _, langPath := range paths {
+		file, err := s.store.GetFile(langPath.Path)
+		if err != nil {
+			continue
+		}
+
+		pathDec, err := dec.Path(langPath)
+		if err != nil {
+			continue
+		}
+
+		symbols, err := pathDec.SymbolsInFile(langPath.Path)
+		if err != nil {
+			continue
+		}
+
+		infos = append(infos, hcl2lsp.WorkspaceSymbols(symbols, params.Query, file.File().Bytes)...)
+	}
+
+	return infos, nil
+}
+
+func (s *Service) HandleTextDocumentSemanticTokensFull(ctx context.Context, params *protocol.SemanticTokensParams) (*protocol.SemanticTokens, error) {
+	fileName := hcl2lsp.FileName(params.TextDocument)
+	file, err := s.store.GetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := decoder.NewDecoder(&s.store)
+	langPath := lang.Path{
+		Path:       fileName,
+		LanguageID: string(file.Language),
+	}
+
+	pathDec, err := dec.Path(langPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := pathDec.SemanticTokensInFile(ctx, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protocol.SemanticTokens{
+		Data: hcl2lsp.SemanticTokens(tokens, file.File().Bytes),
+	}, nil
+}
+
+func (s *Service) HandleTextDocumentSemanticTokensRange(ctx context.Context, params *protocol.SemanticTokensRangeParams) (*protocol.SemanticTokens, error) {
+	fileName := hcl2lsp.FileName(params.TextDocument)
+	file, err := s.store.GetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := decoder.NewDecoder(&s.store)
+	langPath := lang.Path{
+		Path:       fileName,
+		LanguageID: string(file.Language),
+	}
+
+	pathDec, err := dec.Path(langPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := pathDec.SemanticTokensInFile(ctx, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens = hcl2lsp.SemanticTokensInRange(tokens, params.Range, file.File().Bytes)
+
+	return &protocol.SemanticTokens{
+		Data: hcl2lsp.SemanticTokens(tokens, file.File().Bytes),
+	}, nil
+}
+
 func (s *Service) HandleTextDocumentDidOpen(ctx context.Context, params *protocol.DidOpenTextDocumentParams) (*[]protocol.Diagnostic, error) {
 	fileName := hcl2lsp.FileNameItem(params.TextDocument)
 	langID, err := languages.NewFromString(string(params.TextDocument.LanguageID))
@@ -155,7 +555,13 @@ func (s *Service) HandleTextDocumentDidOpen(ctx context.Context, params *protoco
 		return nil, err
 	}
 
+	if langID == languages.NomadVars {
+		s.workspace.IndexSiblings(fileName)
+	}
+
 	newFile := store.NewDocument(langID)
+	newFile.SetOpen(true)
+	newFile.SetVersion(params.TextDocument.Version)
 	_, diags := newFile.ParseHCL([]byte(params.TextDocument.Text), fileName)
 	file := s.store.AddFile(fileName, newFile)
 
@@ -174,7 +580,9 @@ func (s *Service) HandleTextDocumentDidOpen(ctx context.Context, params *protoco
 
 	file.UpdateReferences(pathDec, fileName)
 
-	diagsPath, err := pathDec.ValidateFile(ctx, fileName)
+	// undeclared `var` and `local` references are reported on open as well,
+	// the same diagnostics are published after changes and pulled by clients
+	diagsPath, err := s.validateFile(ctx, pathDec, langPath)
 	if err != nil {
 		return nil, err
 	}
@@ -186,10 +594,8 @@ func (s *Service) HandleTextDocumentDidOpen(ctx context.Context, params *protoco
 	return &lspDiags, nil
 }
 
-func (s *Service) HandleTextDocumentDidChange(ctx context.Context, params *protocol.DidChangeTextDocumentParams) (*[]protocol.Diagnostic, error) {
-	changesCount := len(params.ContentChanges)
-
-	if changesCount == 0 {
+func (s *Service) HandleTextDocumentDidChange(ctx context.Context, params *DidChangeTextDocumentParams) (*[]protocol.Diagnostic, error) {
+	if len(params.ContentChanges) == 0 {
 		return nil, nil
 	}
 
@@ -199,7 +605,25 @@ func (s *Service) HandleTextDocumentDidChange(ctx context.Context, params *proto
 		return nil, err
 	}
 
-	_, diags := file.UpdateHCL([]byte(params.ContentChanges[changesCount-1].Text), fileName)
+	// offsets of a change depend on the content after the previous ones,
+	// they are computed while the document is locked
+	edits := make([]store.Edit, 0, len(params.ContentChanges))
+	for _, change := range params.ContentChanges {
+		edits = append(edits, func(src []byte) (int, int, []byte) {
+			if change.Range == nil {
+				return 0, len(src), []byte(change.Text)
+			}
+
+			start, end := hcl2lsp.Offsets(*change.Range, src)
+			return start, end, []byte(change.Text)
+		})
+	}
+
+	_, diags, ok := file.EditHCL(params.TextDocument.Version, edits, fileName)
+	if !ok {
+		s.logger.Warn("dropped changes of an older version", "path", fileName, "version", params.TextDocument.Version)
+		return nil, nil
+	}
 
 	dec := decoder.NewDecoder(&s.store)
 	langPath := lang.Path{
@@ -216,39 +640,194 @@ func (s *Service) HandleTextDocumentDidChange(ctx context.Context, params *proto
 
 	file.UpdateReferences(pathDec, fileName)
 
+	validationDiags, err := s.validateFile(ctx, pathDec, langPath)
+	if err != nil {
+		return nil, err
+	}
+
+	diags = diags.Extend(validationDiags)
+
+	s.logger.Info(fmt.Sprintf("diags: %+v", diags))
+
+	lspDiags := hcl2lsp.Diagnostics(diags)
+
+	return &lspDiags, nil
+}
+
+// validateFile returns reference and schema diagnostics of an already parsed file
+func (s *Service) validateFile(ctx context.Context, pathDec *decoder.PathDecoder, langPath lang.Path) (hcl.Diagnostics, error) {
 	pathContext, err := s.store.PathContext(langPath)
 	if err != nil {
 		return nil, err
 	}
 
 	diagMap := validation.UnreferencedOrigins(ctx, pathContext)
-	originsDiags := hcl.Diagnostics{}
+	diags := hcl.Diagnostics{}
 	for _, v := range diagMap {
-		originsDiags = originsDiags.Extend(v)
+		diags = diags.Extend(v)
+	}
+
+	schemaDiags, err := pathDec.ValidateFile(ctx, langPath.Path)
+	if err != nil {
+		return nil, err
 	}
 
-	diags = diags.Extend(originsDiags)
+	return diags.Extend(schemaDiags), nil
+}
 
-	schemaDiags, err := pathDec.ValidateFile(ctx, fileName)
+func (s *Service) HandleTextDocumentCodeAction(ctx context.Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
+	fileName := hcl2lsp.FileName(params.TextDocument)
+	file, err := s.store.GetFile(fileName)
 	if err != nil {
 		return nil, err
 	}
 
-	diags = diags.Extend(schemaDiags)
+	dec := decoder.NewDecoder(&s.store)
+	langPath := lang.Path{
+		Path:       fileName,
+		LanguageID: string(file.Language),
+	}
 
-	s.logger.Info(fmt.Sprintf("diags: %+v", diags))
+	pathDec, err := dec.Path(langPath)
+	if err != nil {
+		return nil, err
+	}
 
-	lspDiags := hcl2lsp.Diagnostics(diags)
+	diags, err := s.validateFile(ctx, pathDec, langPath)
+	if err != nil {
+		return nil, err
+	}
 
-	return &lspDiags, nil
+	start := hcl2lsp.Position(params.Range.Start, file.File().Bytes)
+	end := hcl2lsp.Position(params.Range.End, file.File().Bytes)
+
+	diagsInRange := hcl.Diagnostics{}
+	for _, diag := range diags {
+		if diag.Subject != nil && diag.Subject.Start.Byte <= end.Byte && diag.Subject.End.Byte >= start.Byte {
+			diagsInRange = append(diagsInRange, diag)
+		}
+	}
+
+	bodySchema := languages.ToSchema(file.Language)
+	fixes := codeactions.QuickFixes(file.File(), &bodySchema, diagsInRange)
+
+	return hcl2lsp.CodeActions(fixes), nil
 }
 
 func (s *Service) HandleTextDocumentDidClose(ctx context.Context, params *protocol.DidCloseTextDocumentParams) error {
-	s.store.RemoveFile(hcl2lsp.FileName(params.TextDocument))
+	fileName := hcl2lsp.FileName(params.TextDocument)
+
+	if !s.workspace.Contains(fileName) {
+		s.store.RemoveFile(fileName)
+		return nil
+	}
+
+	// files within the workspace stay indexed with their content on disk
+	if file, err := s.store.GetFile(fileName); err == nil {
+		file.SetOpen(false)
+	}
+
+	if err := s.workspace.IndexFile(fileName); err != nil {
+		s.store.RemoveFile(fileName)
+	}
 
 	return nil
 }
 
+func (s *Service) HandleTextDocumentDiagnostic(ctx context.Context, params *DocumentDiagnosticParams) (any, error) {
+	fileName := hcl2lsp.FileName(params.TextDocument)
+	file, err := s.store.GetFile(fileName)
+	if err != nil {
+		// files are indexed lazily when the workspace is not fully indexed yet
+		if err := s.workspace.IndexFile(fileName); err != nil {
+			return nil, err
+		}
+
+		if file, err = s.store.GetFile(fileName); err != nil {
+			return nil, err
+		}
+	}
+
+	id := s.resultID(fileName, file)
+	if params.PreviousResultID == id {
+		return &UnchangedDocumentDiagnosticReport{
+			Kind:     DocumentDiagnosticReportKindUnchanged,
+			ResultID: id,
+		}, nil
+	}
+
+	diags, err := s.fileDiagnostics(ctx, fileName, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FullDocumentDiagnosticReport{
+		Kind:     DocumentDiagnosticReportKindFull,
+		ResultID: id,
+		Items:    hcl2lsp.Diagnostics(diags),
+	}, nil
+}
+
+func (s *Service) HandleWorkspaceDiagnostic(ctx context.Context, params *WorkspaceDiagnosticParams) (*WorkspaceDiagnosticReport, error) {
+	previousResultIDs := make(map[string]string, len(params.PreviousResultIDs))
+	for _, prev := range params.PreviousResultIDs {
+		previousResultIDs[prev.URI.Filename()] = prev.Value
+	}
+
+	paths := s.store.Paths(ctx)
+	slices.SortFunc(paths, func(a, b lang.Path) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	report := &WorkspaceDiagnosticReport{
+		Items: make([]any, 0, len(paths)),
+	}
+
+	for _, langPath := range paths {
+		file, err := s.store.GetFile(langPath.Path)
+		if err != nil {
+			continue
+		}
+
+		var version *int32
+		if file.IsOpen() {
+			v := file.Version()
+			version = &v
+		}
+
+		id := s.resultID(langPath.Path, file)
+		if previousResultIDs[langPath.Path] == id {
+			report.Items = append(report.Items, &WorkspaceUnchangedDocumentDiagnosticReport{
+				UnchangedDocumentDiagnosticReport: UnchangedDocumentDiagnosticReport{
+					Kind:     DocumentDiagnosticReportKindUnchanged,
+					ResultID: id,
+				},
+				URI:     hcl2lsp.URI(langPath.Path),
+				Version: version,
+			})
+			continue
+		}
+
+		diags, err := s.fileDiagnostics(ctx, langPath.Path, file)
+		if err != nil {
+			s.logger.Warn("failed to collect diagnostics", "path", langPath.Path, "error", err.Error())
+			continue
+		}
+
+		report.Items = append(report.Items, &WorkspaceFullDocumentDiagnosticReport{
+			FullDocumentDiagnosticReport: FullDocumentDiagnosticReport{
+				Kind:     DocumentDiagnosticReportKindFull,
+				ResultID: id,
+				Items:    hcl2lsp.Diagnostics(diags),
+			},
+			URI:     hcl2lsp.URI(langPath.Path),
+			Version: version,
+		})
+	}
+
+	return report, nil
+}
+
 func (s *Service) HandleTextDocumentFormatting(ctx context.Context, params *protocol.DocumentFormattingParams) ([]protocol.TextEdit, error) {
 	fileName := hcl2lsp.FileName(params.TextDocument)
 	file, err := s.store.GetFile(fileName)
@@ -256,13 +835,13 @@ func (s *Service) HandleTextDocumentFormatting(ctx context.Context, params *prot
 		return nil, err
 	}
 
-	outBytes := hclwrite.Format(file.HCLFile.Bytes)
+	outBytes := hclwrite.Format(file.File().Bytes)
 
 	var edits []protocol.TextEdit
 
-	if !bytes.Equal(file.HCLFile.Bytes, outBytes) {
+	if !bytes.Equal(file.File().Bytes, outBytes) {
 		startPos := protocol.Position{Line: 0, Character: 0}
-		endPos := getLastPostionFromBytes(file.HCLFile.Bytes)
+		endPos := getLastPostionFromBytes(file.File().Bytes)
 
 		edits = append(edits, protocol.TextEdit{
 			Range: protocol.Range{
@@ -0,0 +1,106 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"go.lsp.dev/protocol"
+
+	"github.com/loczek/nomad-ls/internal/hcl2lsp"
+	"github.com/loczek/nomad-ls/internal/references"
+	"github.com/loczek/nomad-ls/internal/store"
+)
+
+// labelCompletions returns labels of targets reachable from pos when pos is within
+// a string of a [references.LabelReference], e.g. port labels of the group,
+// hcl-lang only completes references as traversals
+func labelCompletions(file *store.Document, pos hcl.Pos) []protocol.CompletionItem {
+	body, ok := file.File().Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	ref, expr := labelExprAtPos(body, "", pos)
+	if expr == nil {
+		return nil
+	}
+
+	editRange := expr.Range()
+	if editRange.Start.Line != editRange.End.Line || editRange.End.Byte < pos.Byte {
+		// unterminated strings span until the next line
+		editRange.End = pos
+	}
+
+	prefix := strings.TrimPrefix(string(hcl.Range{Start: editRange.Start, End: pos}.SliceBytes(file.File().Bytes)), `"`)
+
+	items := make([]protocol.CompletionItem, 0)
+	for _, target := range file.RefTargets() {
+		if target.ScopeId != ref.ScopeId || len(target.LocalAddr) != 2 {
+			continue
+		}
+
+		if target.TargetableFromRangePtr == nil || !target.TargetableFromRangePtr.ContainsPos(pos) {
+			continue
+		}
+
+		step, ok := target.LocalAddr[1].(lang.AttrStep)
+		if !ok {
+			continue
+		}
+
+		label := step.Name
+		if !strings.HasPrefix(label, prefix) {
+			continue
+		}
+
+		items = append(items, protocol.CompletionItem{
+			Label:  label,
+			Kind:   protocol.CompletionItemKindValue,
+			Detail: target.FriendlyName(),
+			TextEdit: &protocol.TextEdit{
+				Range:   hcl2lsp.Range(editRange),
+				NewText: fmt.Sprintf("%q", label),
+			},
+		})
+	}
+
+	return items
+}
+
+// labelExprAtPos finds the string expression at pos referring to a label
+func labelExprAtPos(body *hclsyntax.Body, blockType string, pos hcl.Pos) (references.LabelReference, hclsyntax.Expression) {
+	for _, attr := range body.Attributes {
+		if !attr.SrcRange.ContainsPos(pos) && attr.SrcRange.End != pos {
+			continue
+		}
+
+		ref, ok := references.LabelReferenceOf(blockType, attr.Name)
+		if !ok {
+			return ref, nil
+		}
+
+		for _, expr := range references.ElementExprs(attr.Expr) {
+			if _, ok := expr.(*hclsyntax.TemplateExpr); !ok {
+				continue
+			}
+
+			rng := expr.Range()
+			if rng.ContainsPos(pos) || rng.End == pos {
+				return ref, expr
+			}
+		}
+
+		return ref, nil
+	}
+
+	for _, block := range body.Blocks {
+		if block.Body.SrcRange.ContainsPos(pos) {
+			return labelExprAtPos(block.Body, block.Type, pos)
+		}
+	}
+
+	return references.LabelReference{}, nil
+}
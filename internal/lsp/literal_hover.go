@@ -0,0 +1,79 @@
+package lsp
+
+import (
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/schema/job"
+	schemautils "github.com/loczek/nomad-ls/internal/schemaUtils"
+	"github.com/loczek/nomad-ls/internal/store"
+)
+
+// literalHover describes the static value of the attribute at pos
+// by constraints of its block, e.g. `90s` = `1m30s` for durations
+// or next launches of cron expressions
+func literalHover(file *store.Document, pos hcl.Pos) (string, bool) {
+	if file.Language != languages.NomadJob {
+		return "", false
+	}
+
+	body, ok := file.File().Body.(*hclsyntax.Body)
+	if !ok {
+		return "", false
+	}
+
+	bodies, blockBody, attr := declaredAttributeAtPos(body, []*schema.BodySchema{job.RootSchema}, pos)
+	if attr == nil {
+		return "", false
+	}
+
+	for _, constraint := range job.BodyConstraints.Of(bodies) {
+		describer, ok := constraint.(schemautils.ValueDescriber)
+		if !ok {
+			continue
+		}
+
+		if desc, ok := describer.Describe(blockBody, attr); ok {
+			return desc, true
+		}
+	}
+
+	return "", false
+}
+
+// attributeAtPos finds the attribute at pos together with the type and body of its block
+func attributeAtPos(body *hclsyntax.Body, blockType string, pos hcl.Pos) (string, *hclsyntax.Body, *hclsyntax.Attribute) {
+	for _, attr := range body.Attributes {
+		if attr.SrcRange.ContainsPos(pos) {
+			return blockType, body, attr
+		}
+	}
+
+	for _, block := range body.Blocks {
+		if block.Body.SrcRange.ContainsPos(pos) {
+			return attributeAtPos(block.Body, block.Type, pos)
+		}
+	}
+
+	return "", nil, nil
+}
+
+// declaredAttributeAtPos finds the attribute at pos together with its body
+// and the body schemas the body is declared with, see [schemautils.DeclaredBodies]
+func declaredAttributeAtPos(body *hclsyntax.Body, bodies []*schema.BodySchema, pos hcl.Pos) ([]*schema.BodySchema, *hclsyntax.Body, *hclsyntax.Attribute) {
+	for _, attr := range body.Attributes {
+		if attr.SrcRange.ContainsPos(pos) {
+			return bodies, body, attr
+		}
+	}
+
+	for _, block := range body.Blocks {
+		if block.Body.SrcRange.ContainsPos(pos) {
+			return declaredAttributeAtPos(block.Body, schemautils.DeclaredBodies(block, bodies), pos)
+		}
+	}
+
+	return nil, nil, nil
+}
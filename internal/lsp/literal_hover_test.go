@@ -0,0 +1,50 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"go.lsp.dev/protocol"
+
+	"github.com/loczek/nomad-ls/internal/hcl2lsp"
+)
+
+const LITERALS_NOMAD_FILE_PATH = "./testdata/literals.nomad.hcl"
+
+func TestLiteralHover(t *testing.T) {
+	s, uri := OpenSampleFile(t, LITERALS_NOMAD_FILE_PATH)
+
+	file, err := s.store.GetFile(uri.Filename())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		pos      protocol.Position
+		expected string
+		ok       bool
+	}{
+		{"nanoseconds", protocol.Position{Line: 4, Character: 21}, "`5000000000` nanoseconds = `5s`", true},
+		{"string", protocol.Position{Line: 12, Character: 15}, "`90s` = `1m30s`", true},
+		{"not a literal", protocol.Position{Line: 7, Character: 17}, "", false},
+		{"outside of attributes", protocol.Position{Line: 1, Character: 2}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, ok := literalHover(file, hcl2lsp.Position(tt.pos, file.File().Bytes))
+			if ok != tt.ok || desc != tt.expected {
+				t.Errorf("expected %q, recieved: %q", tt.expected, desc)
+			}
+		})
+	}
+}
+
+func TestNanosecondDurationIsValid(t *testing.T) {
+	for _, d := range CollectDiagnostics(t, LITERALS_NOMAD_FILE_PATH) {
+		if d.Severity == hcl.DiagError {
+			t.Errorf("unexpected diagnostic: %s at %v", d.Summary, d.Subject)
+		}
+	}
+}
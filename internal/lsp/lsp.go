@@ -10,32 +10,70 @@ import (
 	"go.lsp.dev/protocol"
 
 	"github.com/loczek/nomad-ls/internal/store"
+	"github.com/loczek/nomad-ls/internal/workspace"
 )
 
 type Service struct {
-	con    jsonrpc2.Conn
-	store  store.Store
-	logger slog.Logger
+	con       jsonrpc2.Conn
+	store     store.Store
+	workspace *workspace.Indexer
+	logger    slog.Logger
+
+	// documents orders synchronization notifications of each document
+	documents *documentQueue
+	// refresh collects requests to refresh diagnostics of clients
+	refresh *debouncer
+
+	diagnosticCapabilities DiagnosticClientCapabilities
 }
 
 func New(con jsonrpc2.Conn, logger slog.Logger) Service {
+	// store copies share the underlying files
+	st := store.NewStore()
+
 	return Service{
-		con:    con,
-		store:  store.NewStore(),
-		logger: logger,
+		con:       con,
+		store:     st,
+		workspace: workspace.NewIndexer(&st, logger),
+		logger:    logger,
+		documents: newDocumentQueue(),
+		refresh:   newDebouncer(refreshDelay),
 	}
 }
 
 func (s *Service) Handle(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) (any, error) {
 	switch req.Method() {
 	case protocol.MethodInitialize:
-		params := protocol.InitializedParams{}
+		params := protocol.InitializeParams{}
 		err := json.Unmarshal(req.Params(), &params)
 		if err != nil {
 			return nil, err
 		}
 
+		err = json.Unmarshal(req.Params(), &s.diagnosticCapabilities)
+		if err != nil {
+			return nil, err
+		}
+
 		return s.HandleInitialize(ctx, &params)
+	case protocol.MethodInitialized:
+		params := protocol.InitializedParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.HandleInitialized(ctx, &params)
+	case protocol.MethodWorkspaceDidChangeWorkspaceFolders:
+		params := protocol.DidChangeWorkspaceFoldersParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return nil, s.HandleWorkspaceDidChangeWorkspaceFolders(ctx, &params)
 	case protocol.MethodTextDocumentHover:
 		params := protocol.HoverParams{}
 		err := json.Unmarshal(req.Params(), &params)
@@ -66,6 +104,116 @@ func (s *Service) Handle(ctx context.Context, reply jsonrpc2.Replier, req jsonrp
 		s.logger.Info(fmt.Sprintf("%+v", params))
 
 		return s.HandleTextDocumentCompletion(ctx, &params)
+	case protocol.MethodTextDocumentDefinition:
+		params := protocol.DefinitionParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleTextDocumentDefinition(ctx, &params)
+	case protocol.MethodTextDocumentReferences:
+		params := protocol.ReferenceParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleTextDocumentReferences(ctx, &params)
+	case protocol.MethodTextDocumentPrepareRename:
+		params := protocol.PrepareRenameParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleTextDocumentPrepareRename(ctx, &params)
+	case protocol.MethodTextDocumentRename:
+		params := protocol.RenameParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleTextDocumentRename(ctx, &params)
+	case protocol.MethodTextDocumentDocumentSymbol:
+		params := protocol.DocumentSymbolParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleTextDocumentDocumentSymbol(ctx, &params)
+	case protocol.MethodWorkspaceSymbol:
+		params := protocol.WorkspaceSymbolParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleWorkspaceSymbol(ctx, &params)
+	case protocol.MethodSemanticTokensFull:
+		params := protocol.SemanticTokensParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleTextDocumentSemanticTokensFull(ctx, &params)
+	case protocol.MethodSemanticTokensRange:
+		params := protocol.SemanticTokensRangeParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleTextDocumentSemanticTokensRange(ctx, &params)
+	case protocol.MethodTextDocumentCodeAction:
+		params := protocol.CodeActionParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleTextDocumentCodeAction(ctx, &params)
+	case MethodTextDocumentDiagnostic:
+		params := DocumentDiagnosticParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleTextDocumentDiagnostic(ctx, &params)
+	case MethodWorkspaceDiagnostic:
+		params := WorkspaceDiagnosticParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleWorkspaceDiagnostic(ctx, &params)
 	case protocol.MethodTextDocumentDidOpen:
 		params := protocol.DidOpenTextDocumentParams{}
 		err := json.Unmarshal(req.Params(), &params)
@@ -75,7 +223,8 @@ func (s *Service) Handle(ctx context.Context, reply jsonrpc2.Replier, req jsonrp
 
 		diags, err := s.HandleTextDocumentDidOpen(ctx, &params)
 
-		if diags != nil {
+		// clients supporting pull diagnostics request them on their own
+		if diags != nil && !s.diagnosticCapabilities.PullSupport() {
 			s.con.Notify(context.Background(), protocol.MethodTextDocumentPublishDiagnostics, protocol.PublishDiagnosticsParams{
 				URI:         params.TextDocument.URI,
 				Version:     uint32(params.TextDocument.Version),
@@ -85,7 +234,7 @@ func (s *Service) Handle(ctx context.Context, reply jsonrpc2.Replier, req jsonrp
 
 		return nil, err
 	case protocol.MethodTextDocumentDidChange:
-		params := protocol.DidChangeTextDocumentParams{}
+		params := DidChangeTextDocumentParams{}
 		err := json.Unmarshal(req.Params(), &params)
 		if err != nil {
 			return nil, err
@@ -93,7 +242,7 @@ func (s *Service) Handle(ctx context.Context, reply jsonrpc2.Replier, req jsonrp
 
 		diags, err := s.HandleTextDocumentDidChange(ctx, &params)
 
-		if diags != nil {
+		if diags != nil && !s.diagnosticCapabilities.PullSupport() {
 			s.con.Notify(context.Background(), protocol.MethodTextDocumentPublishDiagnostics, protocol.PublishDiagnosticsParams{
 				URI:         params.TextDocument.URI,
 				Version:     uint32(params.TextDocument.Version),
@@ -101,6 +250,11 @@ func (s *Service) Handle(ctx context.Context, reply jsonrpc2.Replier, req jsonrp
 			})
 		}
 
+		// var files depend on variables declared by the job
+		if err == nil {
+			s.updateDependentDiagnostics(context.Background(), params.TextDocument.URI.Filename())
+		}
+
 		return nil, err
 	case protocol.MethodTextDocumentDidClose:
 		params := protocol.DidCloseTextDocumentParams{}
@@ -109,7 +263,14 @@ func (s *Service) Handle(ctx context.Context, reply jsonrpc2.Replier, req jsonrp
 			return nil, err
 		}
 
-		return nil, s.HandleTextDocumentDidClose(ctx, &params)
+		err = s.HandleTextDocumentDidClose(ctx, &params)
+
+		// unsaved changes of the job are discarded on close
+		if err == nil {
+			s.updateDependentDiagnostics(context.Background(), params.TextDocument.URI.Filename())
+		}
+
+		return nil, err
 	case protocol.MethodTextDocumentFormatting:
 		params := protocol.DocumentFormattingParams{}
 
@@ -119,6 +280,16 @@ func (s *Service) Handle(ctx context.Context, reply jsonrpc2.Replier, req jsonrp
 		}
 
 		return s.HandleTextDocumentFormatting(ctx, &params)
+	case protocol.MethodWorkspaceExecuteCommand:
+		params := protocol.ExecuteCommandParams{}
+		err := json.Unmarshal(req.Params(), &params)
+		if err != nil {
+			return nil, err
+		}
+
+		s.logger.Info(fmt.Sprintf("%+v", params))
+
+		return s.HandleWorkspaceExecuteCommand(ctx, &params)
 	case protocol.MethodShutdown:
 		ctx.Done()
 		return nil, nil
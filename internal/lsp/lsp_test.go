@@ -1,12 +1,12 @@
 package lsp
 
 import (
-	"os"
+	"context"
+	"log/slog"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hclparse"
 	"go.lsp.dev/protocol"
 )
 
@@ -40,15 +40,24 @@ func TestServiceBlockHoverInformation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			hclFile := LoadSampleFile(tt.filePath)
+			s, uri := OpenSampleFile(t, tt.filePath)
 
-			predictedCount := CalculateByteOffset(tt.pos, hclFile.Bytes)
-
-			blocks := CollectHoverInfo(hclFile.Body, hcl.Pos{
-				Line:   int(tt.pos.Line),
-				Column: int(tt.pos.Character),
-				Byte:   int(predictedCount),
+			hover, err := s.HandleTextDocumentHover(context.Background(), &protocol.HoverParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+					Position:     tt.pos,
+				},
 			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if hover == nil {
+				t.Fatal("expected hover information")
+			}
+
+			// the description follows the block header
+			blocks := strings.Split(hover.Contents.Value, "\n\n")
 
 			t.Logf("blocks: %v", blocks)
 
@@ -62,33 +71,33 @@ func TestServiceBlockHoverInformation(t *testing.T) {
 }
 
 func TestBlockCompletion(t *testing.T) {
-	hclFile := LoadSampleFile(LOKI_NOMAD_FILE_PATH)
+	s, uri := OpenSampleFile(t, LOKI_NOMAD_FILE_PATH)
 
 	pos := protocol.Position{Line: 14, Character: 0}
 
-	predictedCount := CalculateByteOffset(pos, hclFile.Bytes)
-
-	blocks := CollectCompletions(hclFile.Body, hcl.Pos{
-		Line:   int(pos.Line),
-		Column: int(pos.Character),
-		Byte:   int(predictedCount),
+	blocks, err := s.HandleTextDocumentCompletion(context.Background(), &protocol.CompletionParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: uri},
+			Position:     pos,
+		},
 	})
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	t.Logf("blocks: %v", blocks)
 
-	if len(blocks) == 0 {
+	if blocks == nil || len(blocks.Items) == 0 {
 		t.Errorf("blocks empty")
 	}
 }
 
 func TestMetaBlockAllowsAnyAttribute(t *testing.T) {
-	hclFile := LoadSampleFile(GENERIC_NOMAD_FILE_PATH)
-
-	diags := CollectDiagnostics(hclFile.Body)
+	diags := CollectDiagnostics(t, GENERIC_NOMAD_FILE_PATH)
 
 	// Filter for errors only (ignore warnings)
 	var errors hcl.Diagnostics
-	for _, d := range *diags {
+	for _, d := range diags {
 		if d.Severity == hcl.DiagError {
 			errors = append(errors, d)
 		}
@@ -102,13 +111,11 @@ func TestMetaBlockAllowsAnyAttribute(t *testing.T) {
 }
 
 func TestDockerLoggingConfigBlock(t *testing.T) {
-	hclFile := LoadSampleFile(DOCKER_LOGGING_NOMAD_FILE_PATH)
-
-	diags := CollectDiagnostics(hclFile.Body)
+	diags := CollectDiagnostics(t, DOCKER_LOGGING_NOMAD_FILE_PATH)
 
 	// Filter for errors only (ignore warnings)
 	var errors hcl.Diagnostics
-	for _, d := range *diags {
+	for _, d := range diags {
 		if d.Severity == hcl.DiagError {
 			errors = append(errors, d)
 		}
@@ -122,13 +129,11 @@ func TestDockerLoggingConfigBlock(t *testing.T) {
 }
 
 func TestInvalidAttributeGeneratesDiagnostic(t *testing.T) {
-	hclFile := LoadSampleFile(INVALID_ATTRIBUTE_NOMAD_FILE_PATH)
-
-	diags := CollectDiagnostics(hclFile.Body)
+	diags := CollectDiagnostics(t, INVALID_ATTRIBUTE_NOMAD_FILE_PATH)
 
 	// Filter for errors only
 	var errors hcl.Diagnostics
-	for _, d := range *diags {
+	for _, d := range diags {
 		if d.Severity == hcl.DiagError {
 			errors = append(errors, d)
 		}
@@ -138,32 +143,41 @@ func TestInvalidAttributeGeneratesDiagnostic(t *testing.T) {
 		t.Error("expected diagnostic error for invalid attribute, but got none")
 	}
 
-	// Verify the error is about an unsupported attribute
+	// Verify the error is about the unexpected attribute
 	found := false
 	for _, d := range errors {
-		if strings.Contains(d.Summary, "Unsupported") || strings.Contains(d.Summary, "unsupported") {
+		if d.Summary == "Unexpected attribute" && strings.Contains(d.Detail, "invalid_attribute_that_should_error") {
 			found = true
-			t.Logf("correctly detected invalid attribute: %s", d.Summary)
+			t.Logf("correctly detected invalid attribute: %s", d.Detail)
 			break
 		}
 	}
 
 	if !found {
-		t.Errorf("expected 'unsupported attribute' error, got: %v", errors)
+		t.Errorf("expected 'unexpected attribute' error, got: %v", errors)
 	}
 }
 
-func LoadSampleFile(path string) *hcl.File {
-	parser := hclparse.NewParser()
+// OpenSampleFile opens the job file in a new service the same way as editors do
+func OpenSampleFile(t *testing.T, path string) (*Service, protocol.DocumentURI) {
+	s := New(nil, *slog.Default())
+
+	return &s, openFile(t, &s, path, "nomad-job")
+}
 
-	file, err := os.ReadFile(path)
+// CollectDiagnostics returns diagnostics of the sample file
+func CollectDiagnostics(t *testing.T, path string) hcl.Diagnostics {
+	s, uri := OpenSampleFile(t, path)
+
+	file, err := s.store.GetFile(uri.Filename())
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
 
-	parser.ParseHCL(file, "nomad-job")
-
-	hclFile := parser.Files()["nomad-job"]
+	diags, err := s.fileDiagnostics(context.Background(), uri.Filename(), file)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	return hclFile
+	return diags
 }
@@ -0,0 +1,103 @@
+package lsp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"go.lsp.dev/protocol"
+
+	"github.com/loczek/nomad-ls/internal/hcl2lsp"
+	"github.com/loczek/nomad-ls/internal/references"
+	"github.com/loczek/nomad-ls/internal/scope"
+	"github.com/loczek/nomad-ls/internal/store"
+)
+
+// nodePropertyCompletions returns interpolations of node properties when pos is within
+// a plain string of an attribute such as `attribute` of `constraint` blocks,
+// completions within `${...}` are provided by hcl-lang, nodeTargets are loaded from a node status
+func nodePropertyCompletions(file *store.Document, nodeTargets reference.Targets, pos hcl.Pos) []protocol.CompletionItem {
+	body, ok := file.File().Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	blockType, _, attr := attributeAtPos(body, "", pos)
+	if attr == nil || !references.IsNodePropertyAttribute(blockType, attr.Name) {
+		return nil
+	}
+
+	expr, ok := attr.Expr.(*hclsyntax.TemplateExpr)
+	if !ok {
+		return nil
+	}
+
+	editRange := expr.Range()
+	if editRange.Start.Line != editRange.End.Line || editRange.End.Byte < pos.Byte {
+		// unterminated strings span until the next line
+		editRange.End = pos
+	}
+
+	if !editRange.ContainsPos(pos) && editRange.End != pos {
+		return nil
+	}
+
+	prefix := strings.TrimPrefix(string(hcl.Range{Start: editRange.Start, End: pos}.SliceBytes(file.File().Bytes)), `"`)
+	if strings.Contains(prefix, "${") {
+		return nil
+	}
+
+	items := make([]protocol.CompletionItem, 0)
+	for _, root := range references.NodePropertyRoots {
+		label := fmt.Sprintf("${%s.}", root)
+		if !strings.HasPrefix(label, prefix) {
+			continue
+		}
+
+		items = append(items, protocol.CompletionItem{
+			Label:            label,
+			Kind:             protocol.CompletionItemKindModule,
+			InsertTextFormat: protocol.InsertTextFormatSnippet,
+			TextEdit: &protocol.TextEdit{
+				Range:   hcl2lsp.Range(editRange),
+				NewText: fmt.Sprintf(`"${%s.$1}"`, root),
+			},
+		})
+	}
+
+	for _, target := range append(slices.Clip(file.RefTargets()), nodeTargets...) {
+		if target.ScopeId != scope.BuiltinScope || len(target.Addr) == 0 {
+			continue
+		}
+
+		root, ok := target.Addr[0].(lang.RootStep)
+		if !ok || !slices.Contains(references.NodePropertyRoots, root.Name) {
+			continue
+		}
+
+		label := fmt.Sprintf("${%s}", target.Addr)
+		if !strings.HasPrefix(label, prefix) {
+			continue
+		}
+
+		items = append(items, protocol.CompletionItem{
+			Label:  label,
+			Kind:   protocol.CompletionItemKindVariable,
+			Detail: target.FriendlyName(),
+			Documentation: protocol.MarkupContent{
+				Kind:  protocol.Markdown,
+				Value: target.Description.Value,
+			},
+			TextEdit: &protocol.TextEdit{
+				Range:   hcl2lsp.Range(editRange),
+				NewText: fmt.Sprintf("%q", label),
+			},
+		})
+	}
+
+	return items
+}
@@ -0,0 +1,42 @@
+package lsp
+
+import (
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/loczek/nomad-ls/internal/references"
+)
+
+// applyInitializationOptions applies options sent by the client on initialize,
+// invalid options are logged as they should not prevent the server from starting
+func (s *Service) applyInitializationOptions(raw any) {
+	if raw == nil {
+		return
+	}
+
+	var opts InitializationOptions
+	b, err := json.Marshal(raw)
+	if err == nil {
+		err = json.Unmarshal(b, &opts)
+	}
+	if err != nil {
+		s.logger.Error("invalid initialization options", slog.String("error", err.Error()))
+		return
+	}
+
+	if opts.NodeStatusFile != "" {
+		path := opts.NodeStatusFile
+		if folders := s.workspace.Folders(); !filepath.IsAbs(path) && len(folders) > 0 {
+			path = filepath.Join(folders[0], path)
+		}
+
+		targets, err := references.LoadNodeStatus(path)
+		if err != nil {
+			s.logger.Error("could not load node status", slog.String("path", path), slog.String("error", err.Error()))
+			return
+		}
+
+		s.store.SetNodeTargets(targets)
+	}
+}
@@ -0,0 +1,61 @@
+package lsp
+
+import (
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestNodeStatusIsLoadedPerService(t *testing.T) {
+	loaded := New(nil, *slog.Default())
+	other := New(nil, *slog.Default())
+
+	path, err := filepath.Abs("../references/testdata/node_status.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded.applyInitializationOptions(map[string]any{"nodeStatusFile": path})
+
+	// within the quotes of `attribute = ""`
+	pos := hcl.Pos{Line: 3, Column: 18, Byte: 44}
+
+	labels := func(s *Service) []string {
+		uri := openFile(t, s, "./testdata/node_status.nomad.hcl", "nomad-job")
+
+		file, err := s.store.GetFile(uri.Filename())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		labels := make([]string, 0)
+		for _, item := range nodePropertyCompletions(file, s.store.NodeTargets(), pos) {
+			labels = append(labels, item.Label)
+		}
+
+		// targets of expressions such as `${meta.rack}` are decoded from the path context
+		pathCtx, err := s.store.PathContext(lang.Path{Path: uri.Filename(), LanguageID: "nomad-job"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, target := range pathCtx.ReferenceTargets {
+			if target.Addr.String() == "meta.rack" {
+				labels = append(labels, "meta.rack")
+			}
+		}
+
+		return labels
+	}
+
+	if recieved := labels(&loaded); !slices.Contains(recieved, "${meta.rack}") || !slices.Contains(recieved, "meta.rack") {
+		t.Errorf("expected meta of the loaded node status to be completed and referenced, recieved: %v", recieved)
+	}
+
+	if recieved := labels(&other); slices.Contains(recieved, "${meta.rack}") || slices.Contains(recieved, "meta.rack") {
+		t.Errorf("expected meta of the node status not to leak into other services, recieved: %v", recieved)
+	}
+}
@@ -0,0 +1,67 @@
+package lsp
+
+import (
+	"encoding/json"
+	"sync"
+
+	"go.lsp.dev/jsonrpc2"
+	"go.lsp.dev/protocol"
+)
+
+// documentQueue runs tasks of a document one after another in the order they were added,
+// tasks of different documents run concurrently
+type documentQueue struct {
+	mu   sync.Mutex
+	last map[string]chan struct{}
+}
+
+func newDocumentQueue() *documentQueue {
+	return &documentQueue{
+		last: make(map[string]chan struct{}),
+	}
+}
+
+// Add runs task once all tasks added before for the same path are done
+func (q *documentQueue) Add(path string, task func()) {
+	done := make(chan struct{})
+
+	q.mu.Lock()
+	prev := q.last[path]
+	q.last[path] = done
+	q.mu.Unlock()
+
+	go func() {
+		defer func() {
+			q.mu.Lock()
+			if q.last[path] == done {
+				delete(q.last, path)
+			}
+			q.mu.Unlock()
+
+			close(done)
+		}()
+
+		if prev != nil {
+			<-prev
+		}
+
+		task()
+	}()
+}
+
+// Go runs the handler of a request in a new goroutine, text document synchronization
+// notifications are handled in the order they arrived for each document
+func (s *Service) Go(req jsonrpc2.Request, handle func()) {
+	switch req.Method() {
+	case protocol.MethodTextDocumentDidOpen, protocol.MethodTextDocumentDidChange, protocol.MethodTextDocumentDidClose:
+		params := struct {
+			TextDocument protocol.TextDocumentIdentifier `json:"textDocument"`
+		}{}
+		if err := json.Unmarshal(req.Params(), &params); err == nil {
+			s.documents.Add(params.TextDocument.URI.Filename(), handle)
+			return
+		}
+	}
+
+	go handle()
+}
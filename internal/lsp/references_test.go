@@ -0,0 +1,77 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.lsp.dev/protocol"
+)
+
+// locationsOf formats locations as file:line:character to compare them regardless of order
+func locationsOf(locations []protocol.Location) []string {
+	formatted := make([]string, 0, len(locations))
+	for _, location := range locations {
+		formatted = append(formatted, fmt.Sprintf("%s:%d:%d", filepath.Base(location.URI.Filename()), location.Range.Start.Line, location.Range.Start.Character))
+	}
+	slices.Sort(formatted)
+
+	return formatted
+}
+
+func TestReferences(t *testing.T) {
+	s := New(nil, *slog.Default())
+	jobURI := openFile(t, &s, REFERENCES_JOB_FILE_PATH, "nomad-job")
+	openFile(t, &s, REFERENCES_VARS_FILE_PATH, "nomad-vars")
+
+	tests := map[string]struct {
+		position           protocol.Position
+		includeDeclaration bool
+		expected           []string
+	}{
+		"label of a variable": {
+			position: protocol.Position{Line: 0, Character: 11},
+			expected: []string{"app.nomad.hcl:14:19", "app.nomad.hcl:18:18", "prod.vars.hcl:0:0"},
+		},
+		"label of a variable with its declaration": {
+			position:           protocol.Position{Line: 0, Character: 11},
+			includeDeclaration: true,
+			expected:           []string{"app.nomad.hcl:0:0", "app.nomad.hcl:14:19", "app.nomad.hcl:18:18", "prod.vars.hcl:0:0"},
+		},
+		"origin of a variable": {
+			position: protocol.Position{Line: 18, Character: 22},
+			expected: []string{"app.nomad.hcl:14:19", "app.nomad.hcl:18:18", "prod.vars.hcl:0:0"},
+		},
+		"attribute of locals": {
+			position: protocol.Position{Line: 5, Character: 3},
+			expected: []string{"app.nomad.hcl:14:32"},
+		},
+		"attribute of locals with its declaration": {
+			position:           protocol.Position{Line: 5, Character: 3},
+			includeDeclaration: true,
+			expected:           []string{"app.nomad.hcl:14:32", "app.nomad.hcl:5:2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			locations, err := s.HandleTextDocumentReferences(context.Background(), &protocol.ReferenceParams{
+				TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+					TextDocument: protocol.TextDocumentIdentifier{URI: jobURI},
+					Position:     test.position,
+				},
+				Context: protocol.ReferenceContext{IncludeDeclaration: test.includeDeclaration},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if recieved := locationsOf(locations); !slices.Equal(recieved, test.expected) {
+				t.Errorf("expected: %v, recieved: %v", test.expected, recieved)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package lsp
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/loczek/nomad-ls/internal/languages"
+)
+
+var errNotRenameable = errors.New("no renameable variable or local found at position")
+
+// renameableRoots lists address roots whose targets can be renamed
+var renameableRoots = []string{"var", "local"}
+
+// renameTarget is a variable or local declaration together with
+// the range of its name, i.e. the label or the attribute name
+type renameTarget struct {
+	path      lang.Path
+	target    reference.Target
+	nameRange hcl.Range
+}
+
+// name returns the name of the variable or local, i.e. the last step of its address
+func (t *renameTarget) name() string {
+	if step, ok := t.target.Addr[1].(lang.AttrStep); ok {
+		return step.Name
+	}
+
+	return ""
+}
+
+// findRenameTarget returns the declaration which either is at pos or is referenced
+// by an origin at pos, and the range of the name under the cursor
+func (s *Service) findRenameTarget(dec *decoder.Decoder, langPath lang.Path, fileName string, pos hcl.Pos) (*renameTarget, hcl.Range, error) {
+	refTargets, err := dec.ReferenceTargetsForOriginAtPos(langPath, fileName, pos)
+	if err == nil && len(refTargets) > 0 {
+		origin := refTargets[0]
+
+		declaration, err := s.declarationAtPos(origin.Path, origin.Range.Filename, origin.Range.Start)
+		if err != nil {
+			return nil, hcl.Range{}, err
+		}
+
+		file, err := s.store.GetFile(origin.OriginRange.Filename)
+		if err != nil {
+			return nil, hcl.Range{}, err
+		}
+
+		nameRange, ok := originNameRange(file.File().Bytes, origin.OriginRange, declaration.target.Addr)
+		if !ok {
+			return nil, hcl.Range{}, errNotRenameable
+		}
+
+		return declaration, nameRange, nil
+	}
+
+	declaration, err := s.declarationAtPos(langPath, fileName, pos)
+	if err != nil {
+		return nil, hcl.Range{}, err
+	}
+
+	if !declaration.target.DefRangePtr.ContainsPos(pos) {
+		return nil, hcl.Range{}, errNotRenameable
+	}
+
+	return declaration, declaration.nameRange, nil
+}
+
+func (s *Service) declarationAtPos(langPath lang.Path, fileName string, pos hcl.Pos) (*renameTarget, error) {
+	pathCtx, err := s.store.PathContext(langPath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.store.GetFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	body, ok := file.File().Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, errNotRenameable
+	}
+
+	targets, _ := pathCtx.ReferenceTargets.InnermostAtPos(fileName, pos)
+	for _, target := range targets {
+		if target.DefRangePtr == nil || len(target.Addr) != 2 {
+			continue
+		}
+
+		if !slices.Contains(renameableRoots, target.Addr[0].String()) {
+			continue
+		}
+
+		nameRange, ok := declarationNameRange(body, *target.DefRangePtr)
+		if !ok {
+			continue
+		}
+
+		return &renameTarget{
+			path:      langPath,
+			target:    target,
+			nameRange: nameRange,
+		}, nil
+	}
+
+	return nil, errNotRenameable
+}
+
+// isDeclared reports whether a variable or local of the same root as the target
+// is already declared with the name
+func (s *Service) isDeclared(declaration *renameTarget, name string) (bool, error) {
+	pathCtx, err := s.store.PathContext(declaration.path)
+	if err != nil {
+		return false, err
+	}
+
+	addr := lang.Address{
+		declaration.target.Addr[0],
+		lang.AttrStep{Name: name},
+	}
+
+	for _, target := range pathCtx.ReferenceTargets {
+		if target.Addr.Equals(addr) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// varFileNameRanges returns ranges of names of attributes assigning the variable
+// at addr in var files next to the job declaring it
+func (s *Service) varFileNameRanges(jobPath string, addr lang.Address) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+	if len(addr) != 2 || addr[0].String() != "var" {
+		return ranges
+	}
+
+	step, ok := addr[1].(lang.AttrStep)
+	if !ok {
+		return ranges
+	}
+
+	for _, path := range s.store.Siblings(jobPath, languages.NomadVars) {
+		file, err := s.store.GetFile(path)
+		if err != nil {
+			continue
+		}
+
+		body, ok := file.File().Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		if attr, ok := body.Attributes[step.Name]; ok {
+			ranges = append(ranges, attr.NameRange)
+		}
+	}
+
+	return ranges
+}
+
+// declarationNameRange finds the block or attribute defined at defRange
+// and returns the range of its name without quotes
+func declarationNameRange(body *hclsyntax.Body, defRange hcl.Range) (hcl.Range, bool) {
+	for _, attr := range body.Attributes {
+		if attr.NameRange == defRange {
+			return attr.NameRange, true
+		}
+	}
+
+	for _, block := range body.Blocks {
+		if block.DefRange() == defRange && len(block.LabelRanges) > 0 {
+			labelRange := block.LabelRanges[len(block.LabelRanges)-1]
+			return shrinkRange(labelRange, 1, 1), true
+		}
+
+		if block.Range().Overlaps(defRange) {
+			if rng, ok := declarationNameRange(block.Body, defRange); ok {
+				return rng, true
+			}
+		}
+	}
+
+	return hcl.Range{}, false
+}
+
+// originNameRange returns the range of the last step of addr within the origin,
+// e.g. `image` within `var.image.tag`
+func originNameRange(src []byte, originRange hcl.Range, addr lang.Address) (hcl.Range, bool) {
+	if originRange.End.Byte > len(src) {
+		return hcl.Range{}, false
+	}
+
+	traversal, diags := hclsyntax.ParseTraversalAbs(src[originRange.Start.Byte:originRange.End.Byte], originRange.Filename, originRange.Start)
+	if diags.HasErrors() || len(traversal) < len(addr) {
+		return hcl.Range{}, false
+	}
+
+	attr, ok := traversal[len(addr)-1].(hcl.TraverseAttr)
+	if !ok {
+		return hcl.Range{}, false
+	}
+
+	// attribute step ranges include the leading dot
+	rng := attr.SrcRange
+	return shrinkRange(rng, rng.End.Byte-rng.Start.Byte-len(attr.Name), 0), true
+}
+
+// shrinkRange trims single-line range by given number of bytes from each side
+func shrinkRange(rng hcl.Range, start int, end int) hcl.Range {
+	rng.Start.Byte += start
+	rng.Start.Column += start
+	rng.End.Byte -= end
+	rng.End.Column -= end
+
+	return rng
+}
@@ -0,0 +1,151 @@
+package lsp
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"go.lsp.dev/protocol"
+)
+
+const (
+	RENAME_JOB_FILE_PATH  = "./testdata/rename/app.nomad.hcl"
+	RENAME_VARS_FILE_PATH = "./testdata/rename/prod.vars.hcl"
+)
+
+const declarationsSrc = `variable "image" {
+  type = string
+}
+
+locals {
+  tag = "latest"
+}
+`
+
+func parseBody(t *testing.T, src string) *hclsyntax.Body {
+	file, diags := hclsyntax.ParseConfig([]byte(src), "job.nomad.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	return file.Body.(*hclsyntax.Body)
+}
+
+// sliceRange returns the source text within a range
+func sliceRange(src string, rng hcl.Range) string {
+	return src[rng.Start.Byte:rng.End.Byte]
+}
+
+func TestDeclarationNameRange(t *testing.T) {
+	body := parseBody(t, declarationsSrc)
+
+	variable := body.Blocks[0]
+	rng, ok := declarationNameRange(body, variable.DefRange())
+	if !ok || sliceRange(declarationsSrc, rng) != "image" {
+		t.Errorf("expected the label without quotes, recieved: %q", sliceRange(declarationsSrc, rng))
+	}
+
+	if rng.Start.Column != 11 || rng.End.Column != 16 {
+		t.Errorf("expected columns of the label without quotes, recieved: %d-%d", rng.Start.Column, rng.End.Column)
+	}
+
+	local := body.Blocks[1].Body.Attributes["tag"]
+	rng, ok = declarationNameRange(body, local.NameRange)
+	if !ok || sliceRange(declarationsSrc, rng) != "tag" {
+		t.Errorf("expected the name of the nested attribute, recieved: %q", sliceRange(declarationsSrc, rng))
+	}
+
+	if _, ok := declarationNameRange(body, local.Expr.Range()); ok {
+		t.Errorf("expected no declaration at the value of the attribute")
+	}
+}
+
+func TestOriginNameRange(t *testing.T) {
+	src := "image = var.image.tag\n"
+	origin := hcl.Range{
+		Filename: "job.nomad.hcl",
+		Start:    hcl.Pos{Line: 1, Column: 9, Byte: 8},
+		End:      hcl.Pos{Line: 1, Column: 22, Byte: 21},
+	}
+	addr := lang.Address{lang.RootStep{Name: "var"}, lang.AttrStep{Name: "image"}}
+
+	rng, ok := originNameRange([]byte(src), origin, addr)
+	if !ok || sliceRange(src, rng) != "image" {
+		t.Fatalf("expected the name of the variable without the dot, recieved: %q", sliceRange(src, rng))
+	}
+
+	if rng.Start.Column != 13 {
+		t.Errorf("expected the column after the dot, recieved: %d", rng.Start.Column)
+	}
+
+	origin.End = hcl.Pos{Line: 1, Column: 30, Byte: 29}
+	if _, ok := originNameRange([]byte(src), origin, addr); ok {
+		t.Errorf("expected origins beyond the source to be ignored")
+	}
+}
+
+func TestShrinkRange(t *testing.T) {
+	rng := shrinkRange(hcl.Range{
+		Start: hcl.Pos{Line: 1, Column: 10, Byte: 9},
+		End:   hcl.Pos{Line: 1, Column: 17, Byte: 16},
+	}, 1, 2)
+
+	if rng.Start.Byte != 10 || rng.Start.Column != 11 || rng.End.Byte != 14 || rng.End.Column != 15 {
+		t.Errorf("expected the range to shrink by 1 and 2 bytes, recieved: %+v", rng)
+	}
+}
+
+func renameImage(t *testing.T, newName string) (*protocol.WorkspaceEdit, error) {
+	s := New(nil, *slog.Default())
+	jobURI := openFile(t, &s, RENAME_JOB_FILE_PATH, "nomad-job")
+	openFile(t, &s, RENAME_VARS_FILE_PATH, "nomad-vars")
+
+	return s.HandleTextDocumentRename(context.Background(), &protocol.RenameParams{
+		TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: jobURI},
+			Position:     protocol.Position{Line: 0, Character: 12},
+		},
+		NewName: newName,
+	})
+}
+
+func TestRenameVariable(t *testing.T) {
+	edit, err := renameImage(t, "repository")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]int)
+	for uri, edits := range edit.Changes {
+		for _, e := range edits {
+			if e.NewText != "repository" {
+				t.Errorf("expected the new name, recieved: %q", e.NewText)
+			}
+		}
+
+		counts[uri.Filename()[strings.LastIndex(uri.Filename(), "/")+1:]] += len(edits)
+	}
+
+	// the label, the origin in locals and the one in the heredoc
+	if counts["app.nomad.hcl"] != 3 {
+		t.Errorf("expected 3 edits of the job, recieved: %d", counts["app.nomad.hcl"])
+	}
+
+	if counts["prod.vars.hcl"] != 1 {
+		t.Errorf("expected the assignment in the var file to be renamed, recieved: %d", counts["prod.vars.hcl"])
+	}
+}
+
+func TestRenameVariableToDeclaredName(t *testing.T) {
+	if _, err := renameImage(t, "tag"); err == nil || !strings.Contains(err.Error(), "var.tag is already declared") {
+		t.Errorf("expected the rename to be rejected, recieved: %v", err)
+	}
+
+	if _, err := renameImage(t, "reference"); err != nil {
+		t.Errorf("expected names of locals not to collide with variables, recieved: %v", err)
+	}
+}
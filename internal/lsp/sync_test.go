@@ -0,0 +1,156 @@
+package lsp
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.lsp.dev/protocol"
+
+	"github.com/loczek/nomad-ls/internal/hcl2lsp"
+)
+
+func changeRange(startLine, startChar, endLine, endChar uint32) *protocol.Range {
+	return &protocol.Range{
+		Start: protocol.Position{Line: startLine, Character: startChar},
+		End:   protocol.Position{Line: endLine, Character: endChar},
+	}
+}
+
+func changeFile(t *testing.T, s *Service, uri protocol.DocumentURI, version int32, changes ...TextDocumentContentChangeEvent) {
+	_, err := s.HandleTextDocumentDidChange(context.Background(), &DidChangeTextDocumentParams{
+		TextDocument: protocol.VersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: protocol.TextDocumentIdentifier{URI: uri},
+			Version:                version,
+		},
+		ContentChanges: changes,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDidChangeAppliesRangedChangesInOrder(t *testing.T) {
+	s := New(nil, *slog.Default())
+	uri := openFile(t, &s, VARS_JOB_FILE_PATH, "nomad-job")
+
+	src, err := os.ReadFile(uri.Filename())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the default moves to the 4th line after the comment is inserted
+	changeFile(t, &s, uri, 1,
+		TextDocumentContentChangeEvent{Range: changeRange(0, 0, 0, 0), Text: "# replicas of the group\n"},
+		TextDocumentContentChangeEvent{Range: changeRange(3, 12, 3, 13), Text: "3"},
+		TextDocumentContentChangeEvent{Range: changeRange(8, 12, 8, 24), Text: "2"},
+	)
+
+	expected := "# replicas of the group\n" + strings.NewReplacer("default = 1", "default = 3", "count = var.replicas", "count = 2").Replace(string(src))
+
+	file, err := s.store.GetFile(uri.Filename())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(file.File().Bytes) != expected {
+		t.Errorf("expected: %q, recieved: %q", expected, file.File().Bytes)
+	}
+
+	if file.Version() != 1 {
+		t.Errorf("expected version 1, recieved: %d", file.Version())
+	}
+}
+
+func TestDidChangeDropsOlderVersions(t *testing.T) {
+	s := New(nil, *slog.Default())
+	uri := openFile(t, &s, VARS_JOB_FILE_PATH, "nomad-job")
+
+	changeFile(t, &s, uri, 2, TextDocumentContentChangeEvent{Range: changeRange(2, 12, 2, 13), Text: "3"})
+	changeFile(t, &s, uri, 1, TextDocumentContentChangeEvent{Range: changeRange(2, 12, 2, 13), Text: "5"})
+
+	file, err := s.store.GetFile(uri.Filename())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(file.File().Bytes), "default = 3") || file.Version() != 2 {
+		t.Errorf("expected the change of version 1 to be dropped, recieved version %d: %s", file.Version(), file.File().Bytes)
+	}
+}
+
+func TestDocumentQueueKeepsOrder(t *testing.T) {
+	q := newDocumentQueue()
+
+	var mu sync.Mutex
+	var order []int
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(1)
+		q.Add("job.nomad.hcl", func() {
+			defer wg.Done()
+
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	for i, v := range order {
+		if i != v {
+			t.Fatalf("expected tasks to run in the order they were added, recieved: %v", order)
+		}
+	}
+}
+
+func TestIndexingWhileEditing(t *testing.T) {
+	s := New(nil, *slog.Default())
+	uri := openFile(t, &s, VARS_JOB_FILE_PATH, "nomad-job")
+
+	folder, err := filepath.Abs("./testdata/vars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.workspace.AddFolder(folder)
+	s.workspace.IndexAll(context.Background())
+
+	// the var file is indexed again while it is read by handlers
+	varsURI := hcl2lsp.URI(filepath.Join(folder, "prod.vars.hcl"))
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for range 20 {
+			s.workspace.IndexAll(context.Background())
+		}
+	})
+	wg.Go(func() {
+		for version := range int32(20) {
+			changeFile(t, &s, uri, version+1, TextDocumentContentChangeEvent{Range: changeRange(2, 12, 2, 13), Text: "3"})
+		}
+	})
+	wg.Go(func() {
+		for range 20 {
+			if _, err := s.HandleTextDocumentDiagnostic(context.Background(), &DocumentDiagnosticParams{
+				TextDocument: protocol.TextDocumentIdentifier{URI: varsURI},
+			}); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	wg.Wait()
+
+	file, err := s.store.GetFile(uri.Filename())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !file.IsOpen() || file.Version() != 20 {
+		t.Errorf("expected the open document to be kept, recieved version: %d", file.Version())
+	}
+}
@@ -0,0 +1,16 @@
+job "literals" {
+  group "web" {
+    task "server" {
+      driver       = "docker"
+      kill_timeout = 5000000000
+
+      config {
+        image = "nginx"
+      }
+    }
+
+    restart {
+      delay = "90s"
+    }
+  }
+}
@@ -0,0 +1,5 @@
+job "app" {
+  constraint {
+    attribute = ""
+  }
+}
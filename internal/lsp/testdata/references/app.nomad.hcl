@@ -0,0 +1,24 @@
+variable "image" {
+  type = string
+}
+
+locals {
+  tag = "latest"
+}
+
+job "app" {
+  group "web" {
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = "${var.image}:${local.tag}"
+      }
+
+      env {
+        IMAGE   = var.image
+        VERSION = var.version
+      }
+    }
+  }
+}
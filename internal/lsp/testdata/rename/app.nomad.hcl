@@ -0,0 +1,31 @@
+variable "image" {
+  type = string
+}
+
+variable "tag" {
+  type    = string
+  default = "latest"
+}
+
+locals {
+  reference = "${var.image}:${var.tag}"
+}
+
+job "app" {
+  group "web" {
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = local.reference
+      }
+
+      template {
+        data        = <<EOT
+IMAGE=${var.image}
+EOT
+        destination = "local/env"
+      }
+    }
+  }
+}
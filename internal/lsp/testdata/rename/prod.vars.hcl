@@ -0,0 +1 @@
+image = "nginx"
@@ -0,0 +1,32 @@
+variable "image" {
+  type    = string
+  default = "nginx"
+}
+
+variable "tag" {
+  type = string
+}
+
+locals {
+  name = upper(var.image)
+  ports = {
+    http = 8080
+    grpc = 9090
+  }
+  reference = "${var.image}:${var.tag}"
+}
+
+job "app" {
+  group "web" {
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = local.reference
+        name  = local.name
+        ports = local.ports
+        tag   = var.tag
+      }
+    }
+  }
+}
@@ -0,0 +1,10 @@
+variable "replicas" {
+  type    = number
+  default = 1
+}
+
+job "app" {
+  group "web" {
+    count = var.replicas
+  }
+}
@@ -0,0 +1,142 @@
+package lsp
+
+import "go.lsp.dev/protocol"
+
+// DidChangeTextDocumentParams mirrors [protocol.DidChangeTextDocumentParams]
+// but keeps the change range optional, which is the only way to tell
+// an incremental change apart from a full document replacement
+type DidChangeTextDocumentParams struct {
+	TextDocument   protocol.VersionedTextDocumentIdentifier `json:"textDocument"`
+	ContentChanges []TextDocumentContentChangeEvent         `json:"contentChanges"`
+}
+
+// TextDocumentContentChangeEvent replaces the whole document when Range is nil
+type TextDocumentContentChangeEvent struct {
+	Range *protocol.Range `json:"range,omitempty"`
+	Text  string          `json:"text"`
+}
+
+// SemanticTokensOptions extends [protocol.SemanticTokensOptions]
+// with the legend and supported requests, which it lacks
+type SemanticTokensOptions struct {
+	protocol.WorkDoneProgressOptions
+
+	Legend protocol.SemanticTokensLegend `json:"legend"`
+	Range  bool                          `json:"range,omitempty"`
+	Full   bool                          `json:"full,omitempty"`
+}
+
+// InitializeResult mirrors [protocol.InitializeResult] with capabilities
+// extended by the ones introduced in LSP 3.17
+type InitializeResult struct {
+	Capabilities ServerCapabilities   `json:"capabilities"`
+	ServerInfo   *protocol.ServerInfo `json:"serverInfo,omitempty"`
+}
+
+// ServerCapabilities extends [protocol.ServerCapabilities] with pull diagnostics
+type ServerCapabilities struct {
+	protocol.ServerCapabilities
+
+	DiagnosticProvider *DiagnosticOptions `json:"diagnosticProvider,omitempty"`
+}
+
+type DiagnosticOptions struct {
+	protocol.WorkDoneProgressOptions
+
+	Identifier            string `json:"identifier,omitempty"`
+	InterFileDependencies bool   `json:"interFileDependencies"`
+	WorkspaceDiagnostics  bool   `json:"workspaceDiagnostics"`
+}
+
+// DiagnosticClientCapabilities holds the client capabilities of pull diagnostics,
+// it is decoded from the initialize params next to [protocol.InitializeParams]
+type DiagnosticClientCapabilities struct {
+	Capabilities struct {
+		TextDocument struct {
+			Diagnostic *struct{} `json:"diagnostic,omitempty"`
+		} `json:"textDocument"`
+		Workspace struct {
+			Diagnostics *struct {
+				RefreshSupport bool `json:"refreshSupport"`
+			} `json:"diagnostics,omitempty"`
+		} `json:"workspace"`
+	} `json:"capabilities"`
+}
+
+func (c *DiagnosticClientCapabilities) PullSupport() bool {
+	return c.Capabilities.TextDocument.Diagnostic != nil
+}
+
+func (c *DiagnosticClientCapabilities) RefreshSupport() bool {
+	return c.Capabilities.Workspace.Diagnostics != nil && c.Capabilities.Workspace.Diagnostics.RefreshSupport
+}
+
+type DocumentDiagnosticParams struct {
+	protocol.WorkDoneProgressParams
+	protocol.PartialResultParams
+
+	TextDocument     protocol.TextDocumentIdentifier `json:"textDocument"`
+	Identifier       string                          `json:"identifier,omitempty"`
+	PreviousResultID string                          `json:"previousResultId,omitempty"`
+}
+
+type DocumentDiagnosticReportKind string
+
+const (
+	DocumentDiagnosticReportKindFull      DocumentDiagnosticReportKind = "full"
+	DocumentDiagnosticReportKindUnchanged DocumentDiagnosticReportKind = "unchanged"
+)
+
+type FullDocumentDiagnosticReport struct {
+	Kind     DocumentDiagnosticReportKind `json:"kind"`
+	ResultID string                       `json:"resultId,omitempty"`
+	Items    []protocol.Diagnostic        `json:"items"`
+}
+
+// UnchangedDocumentDiagnosticReport tells the client to keep
+// the diagnostics of the previous result
+type UnchangedDocumentDiagnosticReport struct {
+	Kind     DocumentDiagnosticReportKind `json:"kind"`
+	ResultID string                       `json:"resultId"`
+}
+
+type PreviousResultID struct {
+	URI   protocol.DocumentURI `json:"uri"`
+	Value string               `json:"value"`
+}
+
+type WorkspaceDiagnosticParams struct {
+	protocol.WorkDoneProgressParams
+	protocol.PartialResultParams
+
+	Identifier        string             `json:"identifier,omitempty"`
+	PreviousResultIDs []PreviousResultID `json:"previousResultIds"`
+}
+
+// WorkspaceDiagnosticReport items are either [WorkspaceFullDocumentDiagnosticReport]
+// or [WorkspaceUnchangedDocumentDiagnosticReport]
+type WorkspaceDiagnosticReport struct {
+	Items []any `json:"items"`
+}
+
+type WorkspaceFullDocumentDiagnosticReport struct {
+	FullDocumentDiagnosticReport
+
+	URI     protocol.DocumentURI `json:"uri"`
+	Version *int32               `json:"version"`
+}
+
+type WorkspaceUnchangedDocumentDiagnosticReport struct {
+	UnchangedDocumentDiagnosticReport
+
+	URI     protocol.DocumentURI `json:"uri"`
+	Version *int32               `json:"version"`
+}
+
+// InitializationOptions are settings of the server sent by the client
+// as `initializationOptions` of the initialize request
+type InitializationOptions struct {
+	// NodeStatusFile is a path to the output of `nomad node status -json <node>`,
+	// relative paths are resolved against the first workspace folder
+	NodeStatusFile string `json:"nodeStatusFile,omitempty"`
+}
@@ -0,0 +1,98 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/store"
+	"github.com/loczek/nomad-ls/internal/variables"
+)
+
+// valueHover describes the value of the `local.<name>` or `var.<name>`
+// reference at pos evaluated from locals and variable defaults of the job
+func valueHover(file *store.Document, pos hcl.Pos) (string, bool) {
+	if file.Language != languages.NomadJob {
+		return "", false
+	}
+
+	body, ok := file.File().Body.(*hclsyntax.Body)
+	if !ok {
+		return "", false
+	}
+
+	traversal, ok := traversalAtPos(body, pos)
+	if !ok {
+		return "", false
+	}
+
+	basedir := filepath.Dir(body.SrcRange.Filename)
+	values := variables.Values(eval.Context, variables.Declared(body), nil)
+	ctx := eval.JobContext(eval.Context(basedir, values), body)
+
+	val, diags := traversal.TraverseAbs(ctx)
+	if !diags.HasErrors() && val.IsWhollyKnown() {
+		return fmt.Sprintf("%s\n\n**Type**: `%s`", valueMarkdown(eval.FormatValue(val)), typeexpr.TypeString(val.Type())), true
+	}
+
+	// the value of a local is rendered partially from its expression
+	if traversal.RootName() != "local" || len(traversal) != 2 {
+		return "_Value is not known statically_", true
+	}
+
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+
+	expr, ok := eval.Locals(body)[attr.Name]
+	if !ok {
+		return "", false
+	}
+
+	partial, unresolved := eval.Partial(expr, ctx, file.File().Bytes)
+
+	desc := valueMarkdown(partial)
+	if len(unresolved) > 0 {
+		desc += fmt.Sprintf("\n\n_Unresolved_: `%s`", strings.Join(unresolved, "`, `"))
+	}
+
+	return desc, true
+}
+
+// traversalAtPos finds the `local.<name>` or `var.<name>` reference at pos
+func traversalAtPos(body *hclsyntax.Body, pos hcl.Pos) (hcl.Traversal, bool) {
+	_, _, attr := attributeAtPos(body, "", pos)
+	if attr == nil {
+		return nil, false
+	}
+
+	for _, traversal := range attr.Expr.Variables() {
+		if !traversal.SourceRange().ContainsPos(pos) {
+			continue
+		}
+
+		switch traversal.RootName() {
+		case "local", "var":
+			return traversal, len(traversal) > 1
+		}
+	}
+
+	return nil, false
+}
+
+// valueMarkdown formats single line values as inline code and others as a code block
+func valueMarkdown(val string) string {
+	val = strings.TrimSpace(val)
+	if !strings.Contains(val, "\n") {
+		return fmt.Sprintf("**Value**: `%s`", val)
+	}
+
+	return fmt.Sprintf("**Value**:\n```hcl\n%s\n```", val)
+}
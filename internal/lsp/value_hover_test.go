@@ -0,0 +1,58 @@
+package lsp
+
+import (
+	"log/slog"
+	"testing"
+
+	"go.lsp.dev/protocol"
+
+	"github.com/loczek/nomad-ls/internal/hcl2lsp"
+)
+
+const VALUES_NOMAD_FILE_PATH = "./testdata/values.nomad.hcl"
+
+func TestValueHover(t *testing.T) {
+	s, uri := OpenSampleFile(t, VALUES_NOMAD_FILE_PATH)
+
+	file, err := s.store.GetFile(uri.Filename())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		pos      protocol.Position
+		expected string
+		ok       bool
+	}{
+		{"local", protocol.Position{Line: 25, Character: 22}, "**Value**: `\"NGINX\"`\n\n**Type**: `string`", true},
+		{"object", protocol.Position{Line: 26, Character: 22}, "**Value**:\n```hcl\n{\n  grpc = 9090\n  http = 8080\n}\n```\n\n**Type**: `object({grpc=number,http=number})`", true},
+		{"partially known local", protocol.Position{Line: 24, Character: 22}, "**Value**: `\"nginx:${var.tag}\"`\n\n_Unresolved_: `var.tag`", true},
+		{"variable without a default", protocol.Position{Line: 27, Character: 20}, "_Value is not known statically_", true},
+		{"variable in a local", protocol.Position{Line: 10, Character: 20}, "**Value**: `\"nginx\"`\n\n**Type**: `string`", true},
+		{"not a reference", protocol.Position{Line: 21, Character: 17}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, ok := valueHover(file, hcl2lsp.Position(tt.pos, file.File().Bytes))
+			if ok != tt.ok || desc != tt.expected {
+				t.Errorf("expected %q, recieved: %q", tt.expected, desc)
+			}
+		})
+	}
+}
+
+func TestValueHoverOfVarFile(t *testing.T) {
+	s := New(nil, *slog.Default())
+	uri := openFile(t, &s, VARS_FILE_PATH, "nomad-vars")
+
+	file, err := s.store.GetFile(uri.Filename())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if desc, ok := valueHover(file, hcl2lsp.Position(protocol.Position{Line: 0, Character: 12}, file.File().Bytes)); ok {
+		t.Errorf("expected no value hover in var files, recieved: %q", desc)
+	}
+}
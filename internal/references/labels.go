@@ -0,0 +1,204 @@
+package references
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/scope"
+)
+
+// LabelReference is an attribute referring to a group-level block by its label,
+// e.g. `port = "http"` of a service refers to `port "http"` of the group network
+type LabelReference struct {
+	BlockType string
+	Attribute string
+
+	// Root is the first step of the address of the referred block
+	Root    string
+	ScopeId lang.ScopeId
+}
+
+var LabelReferences = []LabelReference{
+	{BlockType: "service", Attribute: "port", Root: "port", ScopeId: scope.PortScope},
+	{BlockType: "check", Attribute: "port", Root: "port", ScopeId: scope.PortScope},
+	// docker driver
+	{BlockType: "config", Attribute: "ports", Root: "port", ScopeId: scope.PortScope},
+	{BlockType: "volume_mount", Attribute: "volume", Root: "volume", ScopeId: scope.VolumeScope},
+}
+
+// groupScopes are scopes of targets declared within a group
+// and unique only within that group
+var groupScopes = []lang.ScopeId{
+	scope.PortScope,
+	scope.VolumeScope,
+}
+
+// LabelReferenceOf returns the label reference of the attribute of a block
+func LabelReferenceOf(blockType string, attrName string) (LabelReference, bool) {
+	for _, ref := range LabelReferences {
+		if ref.BlockType == blockType && ref.Attribute == attrName {
+			return ref, true
+		}
+	}
+
+	return LabelReference{}, false
+}
+
+// ElementExprs returns elements of a list expression or the expression itself,
+// e.g. labels of an attribute taking one or a list of them
+func ElementExprs(expr hclsyntax.Expression) []hclsyntax.Expression {
+	if tuple, ok := expr.(*hclsyntax.TupleConsExpr); ok {
+		return tuple.Exprs
+	}
+
+	return []hclsyntax.Expression{expr}
+}
+
+// PortLabels returns labels of `port` blocks declared in `network` blocks of the body,
+// it is not ok when ports are declared by dynamic blocks and the labels are incomplete
+func PortLabels(body *hclsyntax.Body) ([]string, bool) {
+	labels := make([]string, 0)
+	complete := true
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "dynamic":
+			if len(block.Labels) > 0 && block.Labels[0] == "port" {
+				complete = false
+			}
+		case "network":
+			for _, port := range block.Body.Blocks {
+				if port.Type == "dynamic" {
+					complete = false
+				}
+
+				if port.Type == "port" && len(port.Labels) > 0 {
+					labels = append(labels, port.Labels[0])
+				}
+			}
+		}
+	}
+
+	return labels, complete
+}
+
+// ScopeGroupTargets makes targets of group scopes only targetable
+// from within the group declaring them. The targets have no type, so they
+// only match origins constrained to their scope, i.e. [LabelOrigins],
+// and not traversals of expressions such as `port.http`
+func ScopeGroupTargets(targets reference.Targets, body *hclsyntax.Body) reference.Targets {
+	groups := groupRanges(body)
+
+	for i, target := range targets {
+		if !isGroupScope(target.ScopeId) || target.RangePtr == nil {
+			continue
+		}
+
+		for _, groupRange := range groups {
+			if !groupRange.Overlaps(*target.RangePtr) {
+				continue
+			}
+
+			target.LocalAddr = target.Addr
+			target.Addr = lang.Address{}
+			target.Type = cty.NilType
+			target.TargetableFromRangePtr = groupRange.Ptr()
+			targets[i] = target
+			break
+		}
+	}
+
+	return targets
+}
+
+// LabelOrigins returns origins of labels in strings of [LabelReferences]
+func LabelOrigins(body *hclsyntax.Body) reference.Origins {
+	return labelOrigins(body, "")
+}
+
+func labelOrigins(body *hclsyntax.Body, blockType string) reference.Origins {
+	origins := make(reference.Origins, 0)
+
+	for _, attr := range body.Attributes {
+		ref, ok := LabelReferenceOf(blockType, attr.Name)
+		if !ok {
+			continue
+		}
+
+		for _, expr := range ElementExprs(attr.Expr) {
+			val, diags := expr.Value(nil)
+			if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || val.Type() != cty.String {
+				continue
+			}
+
+			origins = append(origins, reference.LocalOrigin{
+				Addr: lang.Address{
+					lang.RootStep{Name: ref.Root},
+					lang.AttrStep{Name: val.AsString()},
+				},
+				Range: expr.Range(),
+				Constraints: reference.OriginConstraints{
+					{OfScopeId: ref.ScopeId},
+				},
+			})
+		}
+	}
+
+	for _, block := range body.Blocks {
+		origins = append(origins, labelOrigins(block.Body, block.Type)...)
+	}
+
+	return origins
+}
+
+// IsLabelRoot reports whether targets with the root are referred to by labels,
+// e.g. `port.http` is only an address of the `port "http"` block
+func IsLabelRoot(root string) bool {
+	for _, ref := range LabelReferences {
+		if ref.Root == root {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsLabelOrigin reports whether the origin is a label collected by [LabelOrigins],
+// other origins with the same address are traversals of expressions
+func IsLabelOrigin(origin reference.LocalOrigin) bool {
+	for _, cons := range origin.Constraints {
+		if isGroupScope(cons.OfScopeId) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isGroupScope(scopeId lang.ScopeId) bool {
+	for _, s := range groupScopes {
+		if s == scopeId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func groupRanges(body *hclsyntax.Body) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+
+	for _, block := range body.Blocks {
+		if block.Type == "group" {
+			ranges = append(ranges, block.Range())
+			continue
+		}
+
+		ranges = append(ranges, groupRanges(block.Body)...)
+	}
+
+	return ranges
+}
@@ -0,0 +1,140 @@
+package references_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/store"
+	"github.com/loczek/nomad-ls/internal/validation"
+)
+
+func TestPortTargetsAreGroupScoped(t *testing.T) {
+	src := []byte(`job "shop" {
+  group "api" {
+    network {
+      port "http" {}
+    }
+
+    service {
+      port = "http"
+    }
+  }
+
+  group "worker" {
+    service {
+      port = "http"
+    }
+  }
+}
+`)
+
+	s := store.NewStore()
+	doc := store.NewDocument(languages.NomadJob)
+	doc.ParseHCL(src, "shop.nomad.hcl")
+	s.AddFile("shop.nomad.hcl", doc)
+
+	langPath := lang.Path{
+		Path:       "shop.nomad.hcl",
+		LanguageID: languages.NomadJob.String(),
+	}
+
+	dec := decoder.NewDecoder(&s)
+	pathDec, err := dec.Path(langPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.UpdateReferences(pathDec, "shop.nomad.hcl"); err != nil {
+		t.Fatal(err)
+	}
+
+	// `port = "http"` of the api group
+	targets, err := dec.ReferenceTargetsForOriginAtPos(langPath, "shop.nomad.hcl", hcl.Pos{Line: 8, Column: 16, Byte: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(targets) != 1 || targets[0].DefRangePtr.Start.Line != 4 {
+		t.Errorf("expected port \"http\" declared on line 4, recieved: %+v", targets)
+	}
+
+	// `port = "http"` of the worker group
+	targets, _ = dec.ReferenceTargetsForOriginAtPos(langPath, "shop.nomad.hcl", hcl.Pos{Line: 14, Column: 16, Byte: 164})
+	if len(targets) != 0 {
+		t.Errorf("expected port of another group not to be found, recieved: %+v", targets)
+	}
+}
+
+func TestLabelTargetsInExpressions(t *testing.T) {
+	src := []byte(`job "shop" {
+  group "api" {
+    network {
+      port "http" {}
+    }
+
+    volume "data" {
+      type   = "host"
+      source = "data"
+    }
+
+    task "server" {
+      volume_mount {
+        volume      = "data"
+        destination = "/data"
+      }
+
+      env {
+        PORT = port.http
+      }
+    }
+  }
+}
+`)
+
+	s := store.NewStore()
+	doc := store.NewDocument(languages.NomadJob)
+	doc.ParseHCL(src, "shop.nomad.hcl")
+	s.AddFile("shop.nomad.hcl", doc)
+
+	langPath := lang.Path{
+		Path:       "shop.nomad.hcl",
+		LanguageID: languages.NomadJob.String(),
+	}
+
+	dec := decoder.NewDecoder(&s)
+	pathDec, err := dec.Path(langPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.UpdateReferences(pathDec, "shop.nomad.hcl"); err != nil {
+		t.Fatal(err)
+	}
+
+	// `port.http` of the env block
+	targets, _ := dec.ReferenceTargetsForOriginAtPos(langPath, "shop.nomad.hcl", hcl.Pos{Line: 19, Column: 18, Byte: 280})
+	if len(targets) != 0 {
+		t.Errorf("expected port \"http\" not to be a target of expressions, recieved: %+v", targets)
+	}
+
+	// `volume = "data"` of the volume_mount block
+	targets, _ = dec.ReferenceTargetsForOriginAtPos(langPath, "shop.nomad.hcl", hcl.Pos{Line: 14, Column: 25, Byte: 207})
+	if len(targets) != 1 || targets[0].DefRangePtr.Start.Line != 7 {
+		t.Errorf("expected volume \"data\" declared on line 7, recieved: %+v", targets)
+	}
+
+	pathCtx, err := s.PathContext(langPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := validation.UnreferencedOrigins(context.Background(), pathCtx)["shop.nomad.hcl"]
+	if len(diags) != 1 || diags[0].Summary != "Unknown variable" || diags[0].Subject.Start.Line != 19 {
+		t.Errorf("expected only port.http of the env block to be reported, recieved: %v", diags)
+	}
+}
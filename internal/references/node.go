@@ -0,0 +1,19 @@
+package references
+
+import "slices"
+
+// nodePropertyAttributes are attributes of placement blocks referring to node properties,
+// e.g. `attribute = "${attr.kernel.name}"` of `constraint` blocks
+var nodePropertyAttributes = map[string][]string{
+	"constraint": {"attribute", "distinct_property"},
+	"affinity":   {"attribute"},
+	"spread":     {"attribute"},
+}
+
+// NodePropertyRoots are roots of interpolations referring to node properties
+var NodePropertyRoots = []string{"attr", "meta", "node"}
+
+// IsNodePropertyAttribute reports whether the attribute of the block refers to a node property
+func IsNodePropertyAttribute(blockType string, attribute string) bool {
+	return slices.Contains(nodePropertyAttributes[blockType], attribute)
+}
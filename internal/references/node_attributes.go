@@ -0,0 +1,219 @@
+package references
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/scope"
+)
+
+// nodeAttribute is a client fingerprint attribute available as `${attr.<name>}`
+type nodeAttribute struct {
+	Name        string
+	Description string
+	Example     string
+}
+
+// nodeAttributes are attributes fingerprinted by Nomad clients, values of all of them
+// are strings as nodes keep attributes in a map of strings, e.g. `driver.docker` is "1",
+// see https://developer.hashicorp.com/nomad/docs/reference/runtime-variable-interpolation#interpreted_node_vars
+var nodeAttributes = []nodeAttribute{
+	// cpu
+	{Name: "cpu.arch", Description: "CPU architecture of the client", Example: "amd64"},
+	{Name: "cpu.modelname", Description: "CPU model name", Example: "Intel(R) Core(TM) i7-8650U CPU @ 1.90GHz"},
+	{Name: "cpu.numcores", Description: "Number of CPU cores on the client", Example: "8"},
+	{Name: "cpu.frequency", Description: "CPU frequency in MHz", Example: "1900"},
+	{Name: "cpu.totalcompute", Description: "Total CPU compute in MHz, the frequency multiplied by the number of cores", Example: "15200"},
+	{Name: "cpu.reservablecores", Description: "Number of cores which can be reserved by tasks using `resources.cores`", Example: "8"},
+
+	// kernel and os
+	{Name: "kernel.arch", Description: "Kernel architecture of the client", Example: "x86_64"},
+	{Name: "kernel.name", Description: "Kernel of the client", Example: "linux"},
+	{Name: "kernel.version", Description: "Version of the client kernel", Example: "5.15.0-91-generic"},
+	{Name: "os.name", Description: "Operating system of the client", Example: "ubuntu"},
+	{Name: "os.version", Description: "Version of the client operating system", Example: "22.04"},
+	{Name: "os.build", Description: "Build number of the client operating system (Windows only)", Example: "10.0.17763.1935"},
+	{Name: "os.signals", Description: "Comma separated list of signals supported by the client", Example: "SIGHUP,SIGINT,SIGQUIT,SIGTERM"},
+	{Name: "os.cgroups.version", Description: "Version of the cgroups hierarchy used by the client", Example: "2"},
+
+	// memory and storage
+	{Name: "memory.totalbytes", Description: "Total memory of the client in bytes", Example: "16589934592"},
+	{Name: "unique.storage.bytesfree", Description: "Free bytes of the volume holding the Nomad data directory", Example: "210843566080"},
+	{Name: "unique.storage.bytestotal", Description: "Total bytes of the volume holding the Nomad data directory", Example: "250790436864"},
+	{Name: "unique.storage.volume", Description: "Volume holding the Nomad data directory", Example: "/dev/nvme0n1p2"},
+	{Name: "unique.cgroup.mountpoint", Description: "Mount point of the cgroup hierarchy", Example: "/sys/fs/cgroup"},
+
+	// network
+	{Name: "unique.hostname", Description: "Hostname of the client", Example: "nomad-client-10-1-2-4"},
+	{Name: "unique.network.ip-address", Description: "IP address fingerprinted by the client from the first interface", Example: "10.1.2.4"},
+
+	// nomad
+	{Name: "nomad.version", Description: "Version of Nomad running on the client", Example: "1.9.4"},
+	{Name: "nomad.revision", Description: "Git revision of Nomad running on the client", Example: "5e40fd5f0b5a6a0e6e1ebb8c36f6b0d5c2e0fd27"},
+	{Name: "nomad.advertise.address", Description: "HTTP address advertised by the client", Example: "10.1.2.4:4646"},
+	{Name: "nomad.service_discovery", Description: "Whether the client supports Nomad native service discovery", Example: "true"},
+
+	// consul
+	{Name: "consul.datacenter", Description: "Datacenter of the Consul agent", Example: "dc1"},
+	{Name: "consul.revision", Description: "Git revision of the Consul agent", Example: "d6b1f2b5"},
+	{Name: "consul.server", Description: "Whether the Consul agent is a server", Example: "false"},
+	{Name: "consul.version", Description: "Version of the Consul agent", Example: "1.20.1"},
+	{Name: "consul.segment", Description: "Network segment of the Consul agent (Consul Enterprise)", Example: "alpha"},
+	{Name: "consul.connect", Description: "Whether Consul service mesh is enabled", Example: "true"},
+	{Name: "consul.grpc", Description: "gRPC port of the Consul agent, `-1` when disabled", Example: "8502"},
+	{Name: "consul.ft.namespaces", Description: "Whether Consul namespaces are supported", Example: "false"},
+	{Name: "unique.consul.name", Description: "Node name of the Consul agent", Example: "nomad-client-10-1-2-4"},
+
+	// vault
+	{Name: "vault.accessible", Description: "Whether Vault is accessible from the client", Example: "true"},
+	{Name: "vault.version", Description: "Version of the Vault server", Example: "1.18.2"},
+	{Name: "vault.cluster_id", Description: "ID of the Vault cluster", Example: "31b19a9b-7e4a-2dd1-a8c3-4b0b2c7f3f0e"},
+	{Name: "vault.cluster_name", Description: "Name of the Vault cluster", Example: "vault-cluster-4b5c3f6a"},
+
+	// drivers
+	{Name: "driver.docker", Description: "Set to `1` when the `docker` driver is detected and healthy", Example: "1"},
+	{Name: "driver.docker.version", Description: "Version of the Docker engine", Example: "27.4.0"},
+	{Name: "driver.docker.bridge_ip", Description: "IP address of the Docker bridge network", Example: "172.17.0.1"},
+	{Name: "driver.docker.os_type", Description: "Operating system type of the Docker engine", Example: "linux"},
+	{Name: "driver.docker.runtimes", Description: "Comma separated list of Docker runtimes", Example: "io.containerd.runc.v2,runc"},
+	{Name: "driver.docker.privileged.enabled", Description: "Whether privileged containers are allowed by the client", Example: "true"},
+	{Name: "driver.docker.volumes.enabled", Description: "Whether host volumes are allowed in Docker tasks", Example: "true"},
+	{Name: "driver.exec", Description: "Set to `1` when the `exec` driver is detected and healthy", Example: "1"},
+	{Name: "driver.raw_exec", Description: "Set to `1` when the `raw_exec` driver is enabled", Example: "1"},
+	{Name: "driver.java", Description: "Set to `1` when the `java` driver is detected and healthy", Example: "1"},
+	{Name: "driver.java.version", Description: "Version of Java", Example: "17.0.9"},
+	{Name: "driver.java.runtime", Description: "Java runtime environment", Example: "OpenJDK Runtime Environment (build 17.0.9+9)"},
+	{Name: "driver.java.vm", Description: "Java virtual machine", Example: "OpenJDK 64-Bit Server VM (build 17.0.9+9, mixed mode, sharing)"},
+	{Name: "driver.qemu", Description: "Set to `1` when the `qemu` driver is detected and healthy", Example: "1"},
+	{Name: "driver.qemu.version", Description: "Version of QEMU", Example: "8.2.2"},
+
+	// aws
+	{Name: "platform.aws.instance-type", Description: "EC2 instance type", Example: "m5.large"},
+	{Name: "platform.aws.placement.availability-zone", Description: "Availability zone of the EC2 instance", Example: "us-east-1a"},
+	{Name: "unique.platform.aws.ami-id", Description: "AMI the EC2 instance was launched from", Example: "ami-0c55b159cbfafe1f0"},
+	{Name: "unique.platform.aws.hostname", Description: "Hostname of the EC2 instance", Example: "ip-10-1-2-4.ec2.internal"},
+	{Name: "unique.platform.aws.instance-id", Description: "ID of the EC2 instance", Example: "i-0b22a22eec53b9321"},
+	{Name: "unique.platform.aws.local-hostname", Description: "Private hostname of the EC2 instance", Example: "ip-10-1-2-4.ec2.internal"},
+	{Name: "unique.platform.aws.local-ipv4", Description: "Private IPv4 address of the EC2 instance", Example: "10.1.2.4"},
+	{Name: "unique.platform.aws.public-hostname", Description: "Public hostname of the EC2 instance", Example: "ec2-54-1-2-4.compute-1.amazonaws.com"},
+	{Name: "unique.platform.aws.public-ipv4", Description: "Public IPv4 address of the EC2 instance", Example: "54.1.2.4"},
+	{Name: "unique.platform.aws.mac", Description: "MAC address of the EC2 instance", Example: "0e:fe:9a:8c:26:9b"},
+
+	// gce
+	{Name: "platform.gce.machine-type", Description: "Machine type of the GCE instance", Example: "n2-standard-4"},
+	{Name: "platform.gce.zone", Description: "Zone of the GCE instance", Example: "us-central1-a"},
+	{Name: "platform.gce.scheduling.automatic-restart", Description: "Whether the GCE instance restarts automatically", Example: "TRUE"},
+	{Name: "platform.gce.scheduling.on-host-maintenance", Description: "Host maintenance behaviour of the GCE instance", Example: "MIGRATE"},
+	{Name: "unique.platform.gce.id", Description: "ID of the GCE instance", Example: "4563957402839456748"},
+	{Name: "unique.platform.gce.hostname", Description: "Hostname of the GCE instance", Example: "nomad-client.c.project.internal"},
+
+	// azure
+	{Name: "platform.azure.location", Description: "Location of the Azure VM", Example: "eastus"},
+	{Name: "platform.azure.vm-size", Description: "Size of the Azure VM", Example: "Standard_D2s_v3"},
+	{Name: "unique.platform.azure.id", Description: "ID of the Azure VM", Example: "13f56399-bd52-4150-9748-7190aae1ff21"},
+	{Name: "unique.platform.azure.name", Description: "Name of the Azure VM", Example: "nomad-client-1"},
+	{Name: "unique.platform.azure.resource-group", Description: "Resource group of the Azure VM", Example: "nomad"},
+	{Name: "unique.platform.azure.local-ipv4", Description: "Private IPv4 address of the Azure VM", Example: "10.1.2.4"},
+	{Name: "unique.platform.azure.public-ipv4", Description: "Public IPv4 address of the Azure VM", Example: "20.1.2.4"},
+}
+
+// NodeAttributeReferences returns `attr.*` targets of the catalogue,
+// attributes and meta of actual nodes are returned by [LoadNodeStatus]
+func NodeAttributeReferences() reference.Targets {
+	targets := make(reference.Targets, 0, len(nodeAttributes))
+	for _, attr := range nodeAttributes {
+		targets = append(targets, nodeTarget("attr", attr.Name, fmt.Sprintf("%s\n\n**Example**: `%s`", attr.Description, attr.Example)))
+	}
+
+	return targets
+}
+
+// nodeStatus is the subset of `nomad node status -json <node>` output
+type nodeStatus struct {
+	Name       string
+	Attributes map[string]string
+	Meta       map[string]string
+}
+
+// LoadNodeStatus loads attributes and meta of nodes from the output of
+// `nomad node status -json <node>`, a single node or a list of them,
+// attributes missing in the catalogue are returned as `attr.*` targets and meta as `meta.*` targets
+func LoadNodeStatus(path string) (reference.Targets, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []nodeStatus
+	if err := json.Unmarshal(src, &nodes); err != nil {
+		var node nodeStatus
+		if err := json.Unmarshal(src, &node); err != nil {
+			return nil, fmt.Errorf("%s is not an output of `nomad node status -json`: %w", path, err)
+		}
+		nodes = []nodeStatus{node}
+	}
+
+	known := make(map[string]bool, len(nodeAttributes))
+	for _, attr := range nodeAttributes {
+		known[attr.Name] = true
+	}
+
+	targets := make(reference.Targets, 0)
+	for _, root := range []string{"attr", "meta"} {
+		examples := make(map[string]string)
+		for _, node := range nodes {
+			values := node.Attributes
+			if root == "meta" {
+				values = node.Meta
+			}
+
+			for name, value := range values {
+				if _, ok := examples[name]; ok || (root == "attr" && known[name]) || !validName(name) {
+					continue
+				}
+				examples[name] = fmt.Sprintf("Fingerprinted by node `%s`\n\n**Example**: `%s`", node.Name, value)
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(examples)) {
+			targets = append(targets, nodeTarget(root, name, examples[name]))
+		}
+	}
+
+	return targets, nil
+}
+
+// validName reports whether the name can be referenced as a traversal
+func validName(name string) bool {
+	for _, step := range strings.Split(name, ".") {
+		if !hclsyntax.ValidIdentifier(step) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nodeTarget returns a target of a node attribute or meta, their values are strings
+func nodeTarget(root string, name string, description string) reference.Target {
+	addr := lang.Address{lang.RootStep{Name: root}}
+	for _, step := range strings.Split(name, ".") {
+		addr = append(addr, lang.AttrStep{Name: step})
+	}
+
+	return reference.Target{
+		Addr:        addr,
+		Type:        cty.String,
+		ScopeId:     scope.BuiltinScope,
+		Description: lang.Markdown(description),
+	}
+}
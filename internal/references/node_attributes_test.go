@@ -0,0 +1,54 @@
+package references_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/references"
+)
+
+func TestLoadNodeStatus(t *testing.T) {
+	targets, err := references.LoadNodeStatus("testdata/node_status.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addrs := make([]string, 0)
+	for _, target := range append(references.NodeAttributeReferences(), targets...) {
+		addrs = append(addrs, target.Addr.String())
+	}
+
+	for _, addr := range []string{"attr.kernel.name", "attr.driver.podman.version", "attr.plugins.cni.version.bridge", "meta.rack", "meta.connect.sidecar_image"} {
+		if !slices.Contains(addrs, addr) {
+			t.Errorf("expected target %q", addr)
+		}
+	}
+
+	for _, addr := range []string{"meta.team/owner"} {
+		if slices.Contains(addrs, addr) {
+			t.Errorf("unexpected target %q", addr)
+		}
+	}
+
+	// attributes of the catalogue are not duplicated
+	kernelNames := 0
+	for _, addr := range addrs {
+		if addr == "attr.kernel.name" {
+			kernelNames++
+		}
+	}
+
+	if kernelNames != 1 {
+		t.Errorf("expected 1 attr.kernel.name target, recieved: %d", kernelNames)
+	}
+}
+
+func TestNodeAttributeReferencesAreStrings(t *testing.T) {
+	for _, target := range references.NodeAttributeReferences() {
+		if !target.Type.Equals(cty.String) {
+			t.Errorf("expected %s to be a string as fingerprinted by clients, recieved: %s", target.Addr, target.Type.FriendlyName())
+		}
+	}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func CommonBuiltinReferences() reference.Targets {
-	return reference.Targets{
+	return append(reference.Targets{
 		{
 			Addr: lang.Address{
 				lang.RootStep{Name: "node"},
@@ -65,5 +65,5 @@ func CommonBuiltinReferences() reference.Targets {
 			ScopeId:     scope.BuiltinScope,
 			Description: lang.Markdown("Client's node pool\n\n**Example**: `prod`"),
 		},
-	}
+	}, NodeAttributeReferences()...)
 }
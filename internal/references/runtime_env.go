@@ -0,0 +1,202 @@
+package references
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+	"github.com/loczek/nomad-ls/internal/scope"
+)
+
+// runtimeVariable is an environment variable Nomad sets in the task environment,
+// referenced as `${NOMAD_ALLOC_DIR}` in strings of the job
+type runtimeVariable struct {
+	Name        string
+	Description string
+	Example     string
+}
+
+// runtimeVariables are variables of every task,
+// see https://developer.hashicorp.com/nomad/docs/reference/runtime-environment-settings
+var runtimeVariables = []runtimeVariable{
+	{Name: "NOMAD_ALLOC_DIR", Description: "Path to the shared `alloc/` directory", Example: "/alloc"},
+	{Name: "NOMAD_TASK_DIR", Description: "Path to the task's `local/` directory", Example: "/local"},
+	{Name: "NOMAD_SECRETS_DIR", Description: "Path to the task's `secrets/` directory", Example: "/secrets"},
+	{Name: "NOMAD_MEMORY_LIMIT", Description: "Memory limit in MB for the task", Example: "256"},
+	{Name: "NOMAD_MEMORY_MAX_LIMIT", Description: "Maximum memory limit in MB for the task when memory oversubscription is enabled", Example: "512"},
+	{Name: "NOMAD_CPU_LIMIT", Description: "CPU limit in MHz for the task", Example: "500"},
+	{Name: "NOMAD_CPU_CORES", Description: "CPU cores reserved for the task in cpuset list notation, only set when `resources.cores` is used", Example: "0-2,7"},
+	{Name: "NOMAD_ALLOC_ID", Description: "Allocation ID of the task", Example: "9afa5da1-8f39-25a2-48dc-ba31fd7c0023"},
+	{Name: "NOMAD_SHORT_ALLOC_ID", Description: "First 8 characters of the allocation ID", Example: "9afa5da1"},
+	{Name: "NOMAD_ALLOC_NAME", Description: "Allocation name of the task", Example: "example.cache[0]"},
+	{Name: "NOMAD_ALLOC_INDEX", Description: "Allocation index, useful to distinguish instances of a group", Example: "0"},
+	{Name: "NOMAD_TASK_NAME", Description: "Name of the task", Example: "redis"},
+	{Name: "NOMAD_GROUP_NAME", Description: "Name of the group", Example: "cache"},
+	{Name: "NOMAD_JOB_ID", Description: "ID of the job, equal to the job name unless the job is dispatched or periodic", Example: "example/periodic-1712345678"},
+	{Name: "NOMAD_JOB_NAME", Description: "Name of the job", Example: "example"},
+	{Name: "NOMAD_JOB_PARENT_ID", Description: "ID of the parent job of dispatched and periodic jobs", Example: "example"},
+	{Name: "NOMAD_DC", Description: "Datacenter in which the allocation is running", Example: "dc1"},
+	{Name: "NOMAD_NAMESPACE", Description: "Namespace in which the allocation is running", Example: "default"},
+	{Name: "NOMAD_REGION", Description: "Region in which the allocation is running", Example: "global"},
+	{Name: "NOMAD_PARENT_CGROUP", Description: "Parent cgroup of the task", Example: "nomad.slice"},
+	{Name: "NOMAD_UNIX_ADDR", Description: "Path to the socket of the Nomad task API", Example: "/secrets/api.sock"},
+	{Name: "NOMAD_TOKEN", Description: "Workload identity token of the task, only set when `identity.env` is enabled", Example: "eyJhbGciOiJSUzI1NiIs..."},
+	{Name: "VAULT_TOKEN", Description: "Vault token of the task, only set when `vault.env` is enabled", Example: "hvs.CAESI..."},
+	{Name: "VAULT_NAMESPACE", Description: "Vault namespace of the task, only set when `vault.namespace` is set", Example: "engineering"},
+	{Name: "VAULT_ADDR", Description: "Address of the Vault server, only set when the `vault` block is used", Example: "https://vault.service.consul:8200"},
+}
+
+// labeledVariable is a variable set for every label of a group, e.g. `NOMAD_PORT_<label>`
+type labeledVariable struct {
+	Prefix string
+	// Description is formatted with the label
+	Description string
+	Example     string
+}
+
+var portVariables = []labeledVariable{
+	{Prefix: "NOMAD_IP_", Description: "Host IP address of the `%s` port", Example: "10.1.2.4"},
+	{Prefix: "NOMAD_PORT_", Description: "Port of the `%s` port, the port inside the container when the port is mapped with `to`", Example: "8080"},
+	{Prefix: "NOMAD_ADDR_", Description: "Host `IP:port` pair of the `%s` port", Example: "10.1.2.4:25432"},
+	{Prefix: "NOMAD_HOST_IP_", Description: "Host IP address of the `%s` port", Example: "10.1.2.4"},
+	{Prefix: "NOMAD_HOST_PORT_", Description: "Host port of the `%s` port", Example: "25432"},
+	{Prefix: "NOMAD_HOST_ADDR_", Description: "Host `IP:port` pair of the `%s` port", Example: "10.1.2.4:25432"},
+	{Prefix: "NOMAD_ALLOC_IP_", Description: "IP address of the `%s` port within the allocation network namespace", Example: "172.26.64.10"},
+	{Prefix: "NOMAD_ALLOC_PORT_", Description: "Port of the `%s` port within the allocation network namespace", Example: "8080"},
+	{Prefix: "NOMAD_ALLOC_ADDR_", Description: "`IP:port` pair of the `%s` port within the allocation network namespace", Example: "172.26.64.10:8080"},
+}
+
+var upstreamVariables = []labeledVariable{
+	{Prefix: "NOMAD_UPSTREAM_IP_", Description: "IP address the `%s` upstream is bound to", Example: "127.0.0.1"},
+	{Prefix: "NOMAD_UPSTREAM_PORT_", Description: "Port the `%s` upstream is bound to", Example: "5432"},
+	{Prefix: "NOMAD_UPSTREAM_ADDR_", Description: "`IP:port` pair the `%s` upstream is bound to", Example: "127.0.0.1:5432"},
+}
+
+var metaVariables = []labeledVariable{
+	{Prefix: "NOMAD_META_", Description: "Value of the `%s` meta key", Example: ""},
+}
+
+var invalidEnvChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// RuntimeEnvTargets returns targets of variables in the task environment,
+// variables of ports, upstreams and meta keys are generated from the job
+// and only targetable from within the block declaring them
+func RuntimeEnvTargets(body *hclsyntax.Body) reference.Targets {
+	targets := make(reference.Targets, 0, len(runtimeVariables))
+
+	for _, v := range runtimeVariables {
+		targets = append(targets, reference.Target{
+			Addr:        lang.Address{lang.RootStep{Name: v.Name}},
+			Type:        cty.String,
+			ScopeId:     scope.BuiltinScope,
+			Description: lang.Markdown(fmt.Sprintf("%s\n\n**Example**: `%s`", v.Description, v.Example)),
+		})
+	}
+
+	return append(targets, labeledRuntimeTargets(body, nil)...)
+}
+
+func labeledRuntimeTargets(body *hclsyntax.Body, parent *hclsyntax.Block) reference.Targets {
+	targets := make(reference.Targets, 0)
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "meta":
+			if parent == nil {
+				continue
+			}
+
+			examples := make(map[string]string)
+			for name, attr := range block.Body.Attributes {
+				val, _ := eval.StaticString(attr.Expr)
+				examples[name] = val
+			}
+
+			targets = append(targets, labeledTargets(metaVariables, examples, parent.Range())...)
+		case "group":
+			ports := make(map[string]string)
+			// ports declared by dynamic blocks have no known labels
+			labels, _ := PortLabels(block.Body)
+			for _, label := range labels {
+				ports[label] = ""
+			}
+
+			targets = append(targets, labeledTargets(portVariables, ports, block.Range())...)
+			targets = append(targets, labeledTargets(upstreamVariables, upstreams(block.Body), block.Range())...)
+		}
+
+		targets = append(targets, labeledRuntimeTargets(block.Body, block)...)
+	}
+
+	return targets
+}
+
+// labeledTargets returns targets of variables for each label,
+// values of labels are used as examples of variables without one
+func labeledTargets(variables []labeledVariable, labels map[string]string, rng hcl.Range) reference.Targets {
+	targets := make(reference.Targets, 0)
+
+	names := make([]string, 0, len(labels))
+	for label := range labels {
+		names = append(names, label)
+	}
+	slices.Sort(names)
+
+	for _, label := range names {
+		for _, v := range variables {
+			example := v.Example
+			if example == "" {
+				example = labels[label]
+			}
+
+			description := fmt.Sprintf(v.Description, label)
+			if example != "" {
+				description += fmt.Sprintf("\n\n**Example**: `%s`", example)
+			}
+
+			targets = append(targets, reference.Target{
+				LocalAddr:              lang.Address{lang.RootStep{Name: v.Prefix + invalidEnvChars.ReplaceAllString(label, "_")}},
+				TargetableFromRangePtr: rng.Ptr(),
+				Type:                   cty.String,
+				ScopeId:                scope.BuiltinScope,
+				Description:            lang.Markdown(description),
+			})
+		}
+	}
+
+	return targets
+}
+
+// upstreams returns destination names of connect upstreams of the group
+func upstreams(body *hclsyntax.Body) map[string]string {
+	names := make(map[string]string)
+
+	for _, block := range body.Blocks {
+		if block.Type != "upstreams" {
+			maps.Copy(names, upstreams(block.Body))
+			continue
+		}
+
+		attr, ok := block.Body.Attributes["destination_name"]
+		if !ok {
+			continue
+		}
+
+		name, ok := eval.StaticString(attr.Expr)
+		if !ok {
+			continue
+		}
+
+		names[name] = ""
+	}
+
+	return names
+}
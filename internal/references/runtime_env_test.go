@@ -0,0 +1,53 @@
+package references_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/loczek/nomad-ls/internal/references"
+)
+
+func TestRuntimeEnvTargets(t *testing.T) {
+	src, err := os.ReadFile("testdata/runtime_env.nomad.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, diags := hclsyntax.ParseConfig(src, "runtime_env.nomad.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	// names are mapped to the first line from which they are targetable, 0 for everywhere
+	scopes := make(map[string]int)
+	for _, target := range references.RuntimeEnvTargets(file.Body.(*hclsyntax.Body)) {
+		if target.TargetableFromRangePtr == nil {
+			scopes[target.Addr.String()] = 0
+			continue
+		}
+
+		scopes[target.LocalAddr.String()] = target.TargetableFromRangePtr.Start.Line
+	}
+
+	expected := map[string]int{
+		"NOMAD_ALLOC_DIR":          0,
+		"NOMAD_JOB_NAME":           0,
+		"NOMAD_META_team":          1,
+		"NOMAD_PORT_http":          6,
+		"NOMAD_HOST_ADDR_admin_ui": 6,
+		"NOMAD_UPSTREAM_ADDR_db":   6,
+	}
+
+	for name, line := range expected {
+		if scope, ok := scopes[name]; !ok || scope != line {
+			t.Errorf("expected %s targetable from line %d, recieved: %d (found: %t)", name, line, scope, ok)
+		}
+	}
+
+	if _, ok := scopes["NOMAD_PORT_admin-ui"]; ok {
+		t.Errorf("expected invalid characters of labels to be replaced")
+	}
+}
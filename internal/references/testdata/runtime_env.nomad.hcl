@@ -0,0 +1,32 @@
+job "shop" {
+  meta {
+    team = "payments"
+  }
+
+  group "api" {
+    network {
+      mode = "bridge"
+      port "http" {}
+      port "admin-ui" {}
+    }
+
+    service {
+      connect {
+        sidecar_service {
+          proxy {
+            upstreams {
+              destination_name = "db"
+              local_bind_port  = 5432
+            }
+          }
+        }
+      }
+    }
+  }
+
+  group "worker" {
+    task "run" {
+      driver = "docker"
+    }
+  }
+}
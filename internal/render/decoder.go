@@ -0,0 +1,677 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// shorthands are attributes of `constraint` and `affinity` blocks setting the operator,
+// `distinct_hosts` is a flag, `distinct_property` sets the attribute and the others set the value
+var shorthands = []string{"distinct_hosts", "distinct_property", "regexp", "semver", "set_contains", "set_contains_all", "set_contains_any", "version"}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// decoder decodes bodies into structs of the Nomad API by their `hcl` tags
+type decoder struct {
+	ctx   *hcl.EvalContext
+	src   []byte
+	diags hcl.Diagnostics
+}
+
+// field is a struct field decoded from an attribute, a block or a label
+type field struct {
+	name  string
+	kind  string
+	index int
+}
+
+func fields(typ reflect.Type) []field {
+	fields := make([]field, 0, typ.NumField())
+
+	for i := range typ.NumField() {
+		tag, ok := typ.Field(i).Tag.Lookup("hcl")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		name, kind, _ := strings.Cut(tag, ",")
+		fields = append(fields, field{name: name, kind: kind, index: i})
+	}
+
+	return fields
+}
+
+func (d *decoder) decodeBody(body *hclsyntax.Body, labels []string, rv reflect.Value) {
+	label := 0
+	blocks := d.expand(body)
+
+	for _, f := range fields(rv.Type()) {
+		fv := rv.Field(f.index)
+
+		switch f.kind {
+		case "label":
+			if label < len(labels) {
+				d.assign(fv, cty.StringVal(labels[label]), body.SrcRange)
+				label++
+			}
+		case "block":
+			for _, block := range blocks {
+				if block.Type == f.name {
+					d.withContext(block.ctx, func() { d.decodeBlock(block.Block, fv) })
+				}
+			}
+		case "remain":
+		default:
+			if attr, ok := body.Attributes[f.name]; ok {
+				d.assign(fv, d.value(attr.Expr), attr.Expr.Range())
+			}
+		}
+	}
+
+	hasShorthands := rv.FieldByName("Operand").IsValid() && rv.FieldByName("RTarget").IsValid()
+	if hasShorthands {
+		d.decodeShorthands(body, rv)
+	}
+
+	d.unsupported(body, rv.Type(), hasShorthands)
+}
+
+// unsupported reports attributes and blocks of the body which are not decoded
+// into any field of the struct, the same way as Nomad rejects them
+func (d *decoder) unsupported(body *hclsyntax.Body, typ reflect.Type, hasShorthands bool) {
+	attrs := make(map[string]bool)
+	blocks := make(map[string]bool)
+	for _, f := range fields(typ) {
+		switch f.kind {
+		case "block":
+			blocks[f.name] = true
+		case "label", "remain":
+		default:
+			attrs[f.name] = true
+		}
+	}
+
+	if hasShorthands {
+		for _, name := range shorthands {
+			attrs[name] = true
+		}
+	}
+
+	names := slices.SortedFunc(maps.Keys(body.Attributes), func(a, b string) int {
+		return body.Attributes[a].SrcRange.Start.Byte - body.Attributes[b].SrcRange.Start.Byte
+	})
+	for _, name := range names {
+		if attrs[name] {
+			continue
+		}
+
+		d.diags = append(d.diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported argument",
+			Detail:   fmt.Sprintf("An argument named %q is not expected here.", name),
+			Subject:  body.Attributes[name].NameRange.Ptr(),
+		})
+	}
+
+	for _, block := range body.Blocks {
+		blockType := block.Type
+		if blockType == "dynamic" && len(block.Labels) == 1 {
+			blockType = block.Labels[0]
+		}
+
+		if blocks[blockType] {
+			continue
+		}
+
+		d.diags = append(d.diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported block type",
+			Detail:   fmt.Sprintf("Blocks of type %q are not expected here.", blockType),
+			Subject:  block.DefRange().Ptr(),
+		})
+	}
+}
+
+// expandedBlock is a block with the context its body is evaluated in,
+// blocks generated by `dynamic` blocks see their iterator
+type expandedBlock struct {
+	*hclsyntax.Block
+	ctx *hcl.EvalContext
+}
+
+// expand returns blocks of the body with `dynamic` blocks replaced by the blocks
+// they generate, dynamic blocks nested in their content are expanded when it is decoded
+func (d *decoder) expand(body *hclsyntax.Body) []expandedBlock {
+	blocks := make([]expandedBlock, 0, len(body.Blocks))
+
+	for _, block := range body.Blocks {
+		if block.Type != "dynamic" {
+			blocks = append(blocks, expandedBlock{block, d.ctx})
+			continue
+		}
+
+		blocks = append(blocks, d.expandDynamic(block)...)
+	}
+
+	return blocks
+}
+
+// expandDynamic generates a block of the content of a `dynamic` block
+// for each element of its `for_each` collection
+func (d *decoder) expandDynamic(block *hclsyntax.Block) []expandedBlock {
+	forEach, hasForEach := block.Body.Attributes["for_each"]
+
+	var content *hclsyntax.Block
+	for _, b := range block.Body.Blocks {
+		if b.Type == "content" {
+			content = b
+		}
+	}
+
+	if len(block.Labels) != 1 || !hasForEach || content == nil {
+		d.diags = append(d.diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid dynamic block",
+			Detail:   "A dynamic block requires the type of generated blocks as its label, a `for_each` attribute and a `content` block.",
+			Subject:  block.DefRange().Ptr(),
+		})
+		return nil
+	}
+
+	iterator := block.Labels[0]
+	if attr, ok := block.Body.Attributes["iterator"]; ok {
+		traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+		if diags.HasErrors() || len(traversal) != 1 {
+			d.diags = append(d.diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid dynamic iterator name",
+				Detail:   "The iterator of a dynamic block must be a single identifier.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			return nil
+		}
+
+		iterator = traversal.RootName()
+	}
+
+	collection, diags := forEach.Expr.Value(d.ctx)
+	d.diags = append(d.diags, diags...)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	if !collection.IsWhollyKnown() || collection.IsNull() || !collection.CanIterateElements() {
+		d.diags = append(d.diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid dynamic for_each value",
+			Detail:   fmt.Sprintf("Cannot use a %s value in for_each, a known collection is required when rendering the job.", collection.Type().FriendlyName()),
+			Subject:  forEach.Expr.Range().Ptr(),
+		})
+		return nil
+	}
+
+	blocks := make([]expandedBlock, 0, collection.LengthInt())
+	for it := collection.ElementIterator(); it.Next(); {
+		key, value := it.Element()
+
+		ctx := d.ctx.NewChild()
+		ctx.Variables = map[string]cty.Value{
+			iterator: cty.ObjectVal(map[string]cty.Value{"key": key, "value": value}),
+		}
+
+		var labels []string
+		if attr, ok := block.Body.Attributes["labels"]; ok {
+			d.withContext(ctx, func() {
+				d.assign(reflect.ValueOf(&labels).Elem(), d.value(attr.Expr), attr.Expr.Range())
+			})
+		}
+
+		blocks = append(blocks, expandedBlock{
+			Block: &hclsyntax.Block{
+				Type:            block.Labels[0],
+				Labels:          labels,
+				Body:            content.Body,
+				TypeRange:       block.LabelRanges[0],
+				OpenBraceRange:  content.OpenBraceRange,
+				CloseBraceRange: content.CloseBraceRange,
+			},
+			ctx: ctx,
+		})
+	}
+
+	return blocks
+}
+
+// withContext runs decode with expressions evaluated in ctx
+func (d *decoder) withContext(ctx *hcl.EvalContext, decode func()) {
+	prev := d.ctx
+	d.ctx = ctx
+	defer func() { d.ctx = prev }()
+
+	decode()
+}
+
+// decodeBlock decodes the block into a struct, a slice of structs or a map,
+// maps of structs are keyed by the label of the block
+func (d *decoder) decodeBlock(block *hclsyntax.Block, fv reflect.Value) {
+	typ := fv.Type()
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		val := reflect.New(typ.Elem())
+		d.decodeBlock(block, val.Elem())
+		fv.Set(val)
+	case reflect.Struct:
+		d.decodeBody(block.Body, block.Labels, fv)
+	case reflect.Slice:
+		val := reflect.New(typ.Elem()).Elem()
+		d.decodeBlock(block, val)
+		fv.Set(reflect.Append(fv, val))
+	case reflect.Map:
+		if fv.IsNil() {
+			fv.Set(reflect.MakeMap(typ))
+		}
+
+		if isStruct(typ.Elem()) {
+			if len(block.Labels) == 0 {
+				return
+			}
+
+			val := reflect.New(typ.Elem()).Elem()
+			d.decodeBlock(block, val)
+			fv.SetMapIndex(reflect.ValueOf(block.Labels[0]), val)
+			return
+		}
+
+		val := reflect.New(typ).Elem()
+		d.assign(val, d.bodyValue(block.Body), block.Body.SrcRange)
+
+		iter := val.MapRange()
+		for iter.Next() {
+			fv.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+}
+
+// decodeShorthands sets the operator of `constraint` and `affinity` blocks
+// from attributes such as `distinct_hosts = true`, the operator defaults to `=`
+func (d *decoder) decodeShorthands(body *hclsyntax.Body, rv reflect.Value) {
+	operand := rv.FieldByName("Operand")
+	if operand.String() == "" {
+		operand.SetString("=")
+	}
+
+	for _, name := range shorthands {
+		attr, ok := body.Attributes[name]
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "distinct_hosts":
+			enabled := reflect.New(reflect.TypeOf(false)).Elem()
+			d.assign(enabled, d.value(attr.Expr), attr.Expr.Range())
+			if !enabled.Bool() {
+				continue
+			}
+		case "distinct_property":
+			d.assign(rv.FieldByName("LTarget"), d.value(attr.Expr), attr.Expr.Range())
+		default:
+			d.assign(rv.FieldByName("RTarget"), d.value(attr.Expr), attr.Expr.Range())
+		}
+
+		operand.SetString(name)
+	}
+}
+
+// bodyValue returns attributes of the body as an object, nested blocks
+// are lists of objects the same way as in task driver configs
+func (d *decoder) bodyValue(body *hclsyntax.Body) cty.Value {
+	attrs := make(map[string]cty.Value, len(body.Attributes))
+	for name, attr := range body.Attributes {
+		attrs[name] = d.value(attr.Expr)
+	}
+
+	blocks := make(map[string][]cty.Value)
+	for _, block := range d.expand(body) {
+		d.withContext(block.ctx, func() {
+			blocks[block.Type] = append(blocks[block.Type], d.bodyValue(block.Body))
+		})
+	}
+
+	for name, vals := range blocks {
+		attrs[name] = cty.TupleVal(vals)
+	}
+
+	return cty.ObjectVal(attrs)
+}
+
+// value evaluates the expression, interpolations of runtime variables such as
+// `${NOMAD_ALLOC_ID}` or `${attr.kernel.name}` are kept as written
+func (d *decoder) value(expr hclsyntax.Expression) cty.Value {
+	switch e := expr.(type) {
+	case *hclsyntax.TemplateExpr:
+		if !e.IsStringLiteral() {
+			return d.template(e.Parts)
+		}
+	case *hclsyntax.TemplateWrapExpr:
+		if d.isRuntime(e.Wrapped) {
+			return d.template([]hclsyntax.Expression{e.Wrapped})
+		}
+	case *hclsyntax.TupleConsExpr:
+		vals := make([]cty.Value, 0, len(e.Exprs))
+		for _, elem := range e.Exprs {
+			vals = append(vals, d.value(elem))
+		}
+
+		return cty.TupleVal(vals)
+	case *hclsyntax.ObjectConsExpr:
+		attrs := make(map[string]cty.Value, len(e.Items))
+		for _, item := range e.Items {
+			key, diags := item.KeyExpr.Value(d.ctx)
+			d.diags = append(d.diags, diags...)
+			if diags.HasErrors() {
+				continue
+			}
+
+			key, err := convert.Convert(key, cty.String)
+			if err != nil || !key.IsKnown() || key.IsNull() {
+				continue
+			}
+
+			attrs[key.AsString()] = d.value(item.ValueExpr)
+		}
+
+		return cty.ObjectVal(attrs)
+	}
+
+	val, diags := expr.Value(d.ctx)
+	d.diags = append(d.diags, diags...)
+	if diags.HasErrors() {
+		return cty.DynamicVal
+	}
+
+	return val
+}
+
+func (d *decoder) template(parts []hclsyntax.Expression) cty.Value {
+	var sb strings.Builder
+
+	for _, part := range parts {
+		if d.isRuntime(part) {
+			sb.WriteString("${" + string(part.Range().SliceBytes(d.src)) + "}")
+			continue
+		}
+
+		val, diags := part.Value(d.ctx)
+		d.diags = append(d.diags, diags...)
+		if diags.HasErrors() || !val.IsWhollyKnown() {
+			return cty.UnknownVal(cty.String)
+		}
+
+		if val.IsNull() {
+			continue
+		}
+
+		str, err := convert.Convert(val, cty.String)
+		if err != nil {
+			d.diags = append(d.diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid template interpolation value",
+				Detail:   fmt.Sprintf("Cannot include the given value in a string template: %s.", err),
+				Subject:  part.Range().Ptr(),
+			})
+			return cty.UnknownVal(cty.String)
+		}
+
+		sb.WriteString(str.AsString())
+	}
+
+	return cty.StringVal(sb.String())
+}
+
+// isRuntime reports whether the expression only references variables
+// which are interpolated by Nomad at runtime
+func (d *decoder) isRuntime(expr hclsyntax.Expression) bool {
+	traversals := expr.Variables()
+	if len(traversals) == 0 {
+		return false
+	}
+
+	for _, traversal := range traversals {
+		// iterators of dynamic blocks are variables of child contexts
+		for ctx := d.ctx; ctx != nil; ctx = ctx.Parent() {
+			if _, ok := ctx.Variables[traversal.RootName()]; ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// assign converts the value to the type of the field
+func (d *decoder) assign(fv reflect.Value, val cty.Value, rng hcl.Range) {
+	if val.IsNull() {
+		return
+	}
+
+	if !val.IsWhollyKnown() {
+		// errors evaluating the value are reported already
+		if d.hasErrorWithin(rng) {
+			return
+		}
+
+		d.diags = append(d.diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unknown value",
+			Detail:   "The value can not be determined when rendering the job.",
+			Subject:  rng.Ptr(),
+		})
+		return
+	}
+
+	if err := set(fv, val); err != nil {
+		d.diags = append(d.diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsuitable value type",
+			Detail:   fmt.Sprintf("Unsuitable value: %s", err),
+			Subject:  rng.Ptr(),
+		})
+	}
+}
+
+func (d *decoder) hasErrorWithin(rng hcl.Range) bool {
+	for _, diag := range d.diags {
+		if diag.Severity == hcl.DiagError && diag.Subject != nil && rng.Overlaps(*diag.Subject) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func set(fv reflect.Value, val cty.Value) error {
+	if val.IsNull() {
+		return nil
+	}
+
+	typ := fv.Type()
+
+	if typ == durationType {
+		return setDuration(fv, val)
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(typ.Elem())
+		if err := set(elem.Elem(), val); err != nil {
+			return err
+		}
+		fv.Set(elem)
+	case reflect.String:
+		str, err := convert.Convert(val, cty.String)
+		if err != nil {
+			return err
+		}
+		fv.SetString(str.AsString())
+	case reflect.Bool:
+		b, err := convert.Convert(val, cty.Bool)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b.True())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := number(val)
+		if err != nil {
+			return err
+		}
+
+		i, acc := n.Int64()
+		if acc != big.Exact || fv.OverflowInt(i) {
+			return fmt.Errorf("%s is not a valid %s", n.String(), typ.Kind())
+		}
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := number(val)
+		if err != nil {
+			return err
+		}
+
+		u, acc := n.Uint64()
+		if acc != big.Exact || fv.OverflowUint(u) {
+			return fmt.Errorf("%s is not a valid %s", n.String(), typ.Kind())
+		}
+		fv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		n, err := number(val)
+		if err != nil {
+			return err
+		}
+
+		f, _ := n.Float64()
+		fv.SetFloat(f)
+	case reflect.Slice:
+		if !val.CanIterateElements() || val.Type().IsMapType() || val.Type().IsObjectType() {
+			return fmt.Errorf("a list is required")
+		}
+
+		slice := reflect.MakeSlice(typ, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := set(elem, v); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		fv.Set(slice)
+	case reflect.Map:
+		if !val.Type().IsMapType() && !val.Type().IsObjectType() {
+			return fmt.Errorf("a map is required")
+		}
+
+		m := reflect.MakeMapWithSize(typ, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := set(elem, v); err != nil {
+				return fmt.Errorf("%s: %w", k.AsString(), err)
+			}
+			m.SetMapIndex(reflect.ValueOf(k.AsString()).Convert(typ.Key()), elem)
+		}
+		fv.Set(m)
+	case reflect.Interface:
+		raw, err := ctyjson.Marshal(val, val.Type())
+		if err != nil {
+			return err
+		}
+
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+
+		if v != nil {
+			fv.Set(reflect.ValueOf(v))
+		}
+	case reflect.Struct:
+		if !val.Type().IsObjectType() {
+			return fmt.Errorf("an object is required")
+		}
+
+		for _, f := range fields(typ) {
+			if !val.Type().HasAttribute(f.name) {
+				continue
+			}
+
+			if err := set(fv.Field(f.index), val.GetAttr(f.name)); err != nil {
+				return fmt.Errorf("%s: %w", f.name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("%s values are not supported", typ.Kind())
+	}
+
+	return nil
+}
+
+// setDuration accepts duration strings such as `"30s"` and numbers of nanoseconds
+func setDuration(fv reflect.Value, val cty.Value) error {
+	if val.Type() == cty.Number {
+		n, err := number(val)
+		if err != nil {
+			return err
+		}
+
+		i, _ := n.Int64()
+		fv.SetInt(i)
+
+		return nil
+	}
+
+	str, err := convert.Convert(val, cty.String)
+	if err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(str.AsString())
+	if err != nil {
+		return err
+	}
+
+	fv.SetInt(int64(duration))
+
+	return nil
+}
+
+func number(val cty.Value) (*big.Float, error) {
+	n, err := convert.Convert(val, cty.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.AsBigFloat(), nil
+}
+
+func isStruct(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	return typ.Kind() == reflect.Struct
+}
@@ -0,0 +1,280 @@
+// Package for rendering job files into the JSON structure of the Nomad API
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/nomad/api"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+	"github.com/loczek/nomad-ls/internal/variables"
+)
+
+// Options are the inputs of a job besides the job file itself
+type Options struct {
+	// VarFiles are paths of var files, relative paths are resolved against the job directory
+	VarFiles []string `json:"varFiles"`
+	// Vars are `<name>=<value>` assignments taking precedence over var files
+	Vars []string `json:"vars"`
+}
+
+// Job evaluates the job file with variables, locals and functions
+// the same way as `nomad job run` does before submitting it
+func Job(path string, src []byte, opts Options) (*api.Job, hcl.Diagnostics) {
+	file, diags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, diags
+	}
+
+	basedir := filepath.Dir(path)
+	declared := variables.Declared(body)
+
+	overrides, overrideDiags := Overrides(basedir, declared, opts)
+	diags = append(diags, overrideDiags...)
+
+	values, valueDiags := inputValues(declared, overrides)
+	diags = append(diags, valueDiags...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var jobBlock *hclsyntax.Block
+	for _, block := range body.Blocks {
+		if block.Type != "job" {
+			continue
+		}
+
+		if jobBlock != nil {
+			return nil, append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate job block",
+				Detail:   "Only one job can be defined in a job file.",
+				Subject:  block.DefRange().Ptr(),
+			})
+		}
+
+		jobBlock = block
+	}
+
+	if jobBlock == nil || len(jobBlock.Labels) != 1 {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing job block",
+			Detail:   "A job file has to define a single `job \"<name>\"` block.",
+			Subject:  body.SrcRange.Ptr(),
+		})
+	}
+
+	d := decoder{
+		ctx: eval.JobContext(eval.RenderContext(basedir, values), body),
+		src: src,
+	}
+
+	job := &api.Job{}
+	d.decodeBody(jobBlock.Body, jobBlock.Labels, reflect.ValueOf(job).Elem())
+	diags = append(diags, d.diags...)
+
+	if job.ID == nil {
+		job.ID = &jobBlock.Labels[0]
+	}
+
+	if job.Name == nil {
+		job.Name = job.ID
+	}
+
+	normalizePorts(job)
+
+	return job, diags
+}
+
+// JSON formats the job the same way as `nomad job run -output`
+func JSON(job *api.Job) ([]byte, error) {
+	return json.MarshalIndent(struct{ Job *api.Job }{job}, "", "    ")
+}
+
+// Overrides returns values of variables assigned by var files and `-var` assignments,
+// values of `-var` assignments are strings unless the variable has a complex type
+func Overrides(basedir string, declared []variables.Variable, opts Options) (map[string]cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	types := make(map[string]cty.Type, len(declared))
+	for _, v := range declared {
+		if _, ok := types[v.Name]; !ok {
+			types[v.Name] = v.Type
+		}
+	}
+
+	overrides := make(map[string]cty.Value)
+
+	for _, path := range opts.VarFiles {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(basedir, path)
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to read var file",
+				Detail:   err.Error(),
+			})
+			continue
+		}
+
+		file, fileDiags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+		diags = append(diags, fileDiags...)
+		if fileDiags.HasErrors() {
+			continue
+		}
+
+		attrs, attrDiags := file.Body.JustAttributes()
+		diags = append(diags, attrDiags...)
+
+		for name, attr := range attrs {
+			if _, ok := types[name]; !ok {
+				diags = append(diags, undeclared(name, attr.NameRange.Ptr()))
+				continue
+			}
+
+			val, valDiags := attr.Expr.Value(nil)
+			diags = append(diags, valDiags...)
+			if !valDiags.HasErrors() {
+				overrides[name] = val
+			}
+		}
+	}
+
+	for _, assignment := range opts.Vars {
+		name, raw, ok := strings.Cut(assignment, "=")
+		if !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid -var option",
+				Detail:   fmt.Sprintf("The given -var option %q is not correctly specified, it must be `<name>=<value>`.", assignment),
+			})
+			continue
+		}
+
+		typ, ok := types[name]
+		if !ok {
+			diags = append(diags, undeclared(name, nil))
+			continue
+		}
+
+		if typ.IsPrimitiveType() || typ == cty.DynamicPseudoType {
+			overrides[name] = cty.StringVal(raw)
+			continue
+		}
+
+		expr, exprDiags := hclsyntax.ParseExpression([]byte(raw), "<value for var."+name+">", hcl.InitialPos)
+		diags = append(diags, exprDiags...)
+		if exprDiags.HasErrors() {
+			continue
+		}
+
+		val, valDiags := expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			overrides[name] = val
+		}
+	}
+
+	return overrides, diags
+}
+
+// inputValues returns values of the variables which have to be set and valid for the job to render
+func inputValues(declared []variables.Variable, overrides map[string]cty.Value) (map[string]cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	for _, v := range declared {
+		override, ok := overrides[v.Name]
+		if !ok {
+			if v.Default == nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Required variable not set",
+					Detail:   fmt.Sprintf("The variable %q has no default value, it has to be set by a var file or `-var`.", v.Name),
+					Subject:  v.DefRange.Ptr(),
+				})
+			}
+			continue
+		}
+
+		if _, err := convert.Convert(override, v.Type); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid value for variable",
+				Detail:   fmt.Sprintf("The value of %q is not compatible with its type: %s", v.Name, err),
+				Subject:  v.DefRange.Ptr(),
+			})
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	values := variables.Values(eval.RenderContext, declared, overrides)
+
+	for _, v := range declared {
+		if _, ok := overrides[v.Name]; !ok {
+			_, defaultDiags := v.DefaultValue(eval.RenderContext)
+			diags = append(diags, defaultDiags...)
+		}
+
+		diags = append(diags, v.Validate(eval.RenderContext, values[v.Name], v.DefRange)...)
+	}
+
+	return values, diags
+}
+
+func undeclared(name string, subject *hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Undefined variable",
+		Detail:   fmt.Sprintf("A value was given for %q which is not declared by the job.", name),
+		Subject:  subject,
+	}
+}
+
+// normalizePorts moves `port` blocks with a static port to reserved ports
+// as the Nomad API expects them
+func normalizePorts(job *api.Job) {
+	networks := make([]*api.NetworkResource, 0)
+	for _, group := range job.TaskGroups {
+		networks = append(networks, group.Networks...)
+
+		for _, task := range group.Tasks {
+			if task.Resources != nil {
+				networks = append(networks, task.Resources.Networks...)
+			}
+		}
+	}
+
+	for _, network := range networks {
+		var dynamic []api.Port
+		for _, port := range network.DynamicPorts {
+			if port.Value > 0 {
+				network.ReservedPorts = append(network.ReservedPorts, port)
+			} else {
+				dynamic = append(dynamic, port)
+			}
+		}
+
+		network.DynamicPorts = dynamic
+	}
+}
@@ -0,0 +1,198 @@
+package render_test
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/loczek/nomad-ls/internal/render"
+)
+
+const jobPath = "testdata/app.nomad.hcl"
+
+func renderJob(t *testing.T, opts render.Options) *api.Job {
+	src, err := os.ReadFile(jobPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, diags := render.Job(jobPath, src, opts)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	return job
+}
+
+func TestJob(t *testing.T) {
+	job := renderJob(t, render.Options{
+		VarFiles: []string{"prod.vars.hcl"},
+		Vars:     []string{`datacenters=["dc1", "dc2"]`},
+	})
+
+	if *job.ID != "app" || *job.Name != "app" {
+		t.Errorf("expected job ID and name from the label, recieved: %s, %s", *job.ID, *job.Name)
+	}
+
+	if len(job.Datacenters) != 2 || job.Datacenters[1] != "dc2" {
+		t.Errorf("expected datacenters from -var, recieved: %v", job.Datacenters)
+	}
+
+	if job.Meta["owner"] != "PLATFORM" {
+		t.Errorf("expected meta evaluated by functions, recieved: %v", job.Meta)
+	}
+
+	if c := job.Constraints[0]; c.Operand != "distinct_hosts" || c.RTarget != "" {
+		t.Errorf("expected distinct_hosts shorthand, recieved: %+v", c)
+	}
+
+	if c := job.Constraints[1]; c.LTarget != "${attr.kernel.name}" || c.Operand != "=" {
+		t.Errorf("expected runtime interpolation kept with the default operator, recieved: %+v", c)
+	}
+
+	group := job.TaskGroups[0]
+	if *group.Count != 3 {
+		t.Errorf("expected count from var file, recieved: %d", *group.Count)
+	}
+
+	if c := group.Constraints[0]; c.Operand != "=" || c.RTarget != "" {
+		t.Errorf("expected disabled distinct_hosts to be ignored, recieved: %+v", c)
+	}
+
+	if *group.RestartPolicy.Delay != 15*time.Second {
+		t.Errorf("expected delay of 15s, recieved: %s", *group.RestartPolicy.Delay)
+	}
+
+	if group.Volumes["data"].Name != "data" || group.Volumes["data"].Source != "data" {
+		t.Errorf("expected volume keyed by label, recieved: %+v", group.Volumes)
+	}
+
+	network := group.Networks[0]
+	if len(network.ReservedPorts) != 1 || network.ReservedPorts[0].Label != "metrics" || network.ReservedPorts[0].Value != 9100 {
+		t.Errorf("expected static port to be reserved, recieved: %+v", network.ReservedPorts)
+	}
+
+	if len(network.DynamicPorts) != 1 || network.DynamicPorts[0].Label != "http" || network.DynamicPorts[0].To != 8080 {
+		t.Errorf("expected dynamic http port, recieved: %+v", network.DynamicPorts)
+	}
+
+	task := group.Tasks[0]
+	if task.Config["image"] != "web-nginx" {
+		t.Errorf("expected image from locals, recieved: %v", task.Config["image"])
+	}
+
+	if mounts, ok := task.Config["mount"].([]any); !ok || len(mounts) != 1 {
+		t.Errorf("expected nested config blocks as a list, recieved: %v", task.Config["mount"])
+	}
+
+	if task.Env["ALLOC"] != "${NOMAD_ALLOC_ID}-web-nginx" {
+		t.Errorf("expected partially interpolated env, recieved: %s", task.Env["ALLOC"])
+	}
+}
+
+func TestJobVarsOverrideVarFiles(t *testing.T) {
+	job := renderJob(t, render.Options{
+		VarFiles: []string{"prod.vars.hcl"},
+		Vars:     []string{"count=5"},
+	})
+
+	if *job.TaskGroups[0].Count != 5 {
+		t.Errorf("expected count from -var, recieved: %d", *job.TaskGroups[0].Count)
+	}
+}
+
+func TestJobInvalidInputs(t *testing.T) {
+	src, err := os.ReadFile(jobPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		opts    render.Options
+		summary string
+	}{
+		"required": {render.Options{}, "Required variable not set"},
+		"undeclared": {
+			render.Options{Vars: []string{"image=nginx", "replicas=2"}},
+			"Undefined variable",
+		},
+		"type": {
+			render.Options{Vars: []string{"image=nginx", "count=many"}},
+			"Invalid value for variable",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := render.Job(jobPath, src, test.opts)
+			if !diags.HasErrors() || diags[0].Summary != test.summary {
+				t.Errorf("expected %q, recieved: %v", test.summary, diags)
+			}
+		})
+	}
+}
+
+func TestJobOutput(t *testing.T) {
+	src, err := os.ReadFile("testdata/dynamic.nomad.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, diags := render.Job("testdata/dynamic.nomad.hcl", src, render.Options{})
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	out, err := render.JSON(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the job in the format of `nomad job run -output`, dynamic blocks are expanded
+	expected, err := os.ReadFile("testdata/dynamic.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out) != string(expected) {
+		t.Errorf("expected: %s\nrecieved: %s", expected, out)
+	}
+}
+
+func TestJobUnsupported(t *testing.T) {
+	src := []byte(`job "api" {
+  priority_class = "high"
+
+  group "api" {
+    dynamic "sidecar" {
+      for_each = ["envoy"]
+
+      content {}
+    }
+
+    task "server" {
+      driver = "docker"
+    }
+  }
+}
+`)
+
+	_, diags := render.Job("api.nomad.hcl", src, render.Options{})
+
+	summaries := make([]string, 0, len(diags))
+	for _, diag := range diags {
+		summaries = append(summaries, diag.Summary+": "+diag.Detail)
+	}
+
+	expected := []string{
+		`Unsupported block type: Blocks of type "sidecar" are not expected here.`,
+		`Unsupported argument: An argument named "priority_class" is not expected here.`,
+	}
+
+	if !slices.Equal(summaries, expected) {
+		t.Errorf("expected: %v, recieved: %v", expected, summaries)
+	}
+}
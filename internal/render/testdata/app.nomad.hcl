@@ -0,0 +1,85 @@
+variable "image" {
+  type = string
+}
+
+variable "count" {
+  type    = number
+  default = 1
+}
+
+variable "datacenters" {
+  type    = list(string)
+  default = ["dc1"]
+}
+
+locals {
+  name = "web-${var.image}"
+}
+
+job "app" {
+  datacenters = var.datacenters
+
+  constraint {
+    distinct_hosts = true
+  }
+
+  constraint {
+    attribute = "${attr.kernel.name}"
+    value     = "linux"
+  }
+
+  meta {
+    owner = upper("platform")
+  }
+
+  group "web" {
+    count = var.count
+
+    constraint {
+      distinct_hosts = false
+    }
+
+    network {
+      port "http" {
+        to = 8080
+      }
+
+      port "metrics" {
+        static = 9100
+      }
+    }
+
+    volume "data" {
+      type   = "host"
+      source = "data"
+    }
+
+    restart {
+      attempts = 2
+      delay    = "15s"
+    }
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = local.name
+        ports = ["http"]
+
+        mount {
+          type   = "bind"
+          target = "/etc/app"
+        }
+      }
+
+      env {
+        ALLOC = "${NOMAD_ALLOC_ID}-${local.name}"
+      }
+
+      resources {
+        cpu    = 100
+        memory = 128
+      }
+    }
+  }
+}
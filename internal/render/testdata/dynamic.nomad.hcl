@@ -0,0 +1,55 @@
+variable "ports" {
+  type = list(object({
+    label = string
+    to    = number
+  }))
+  default = [
+    { label = "http", to = 8080 },
+    { label = "grpc", to = 9090 },
+  ]
+}
+
+job "api" {
+  datacenters = ["dc1"]
+
+  meta {
+    team = "platform"
+  }
+
+  group "api" {
+    network {
+      dynamic "port" {
+        for_each = var.ports
+        labels   = [port.value.label]
+
+        content {
+          to = port.value.to
+        }
+      }
+    }
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = "api:1.0"
+        ports = [for p in var.ports : p.label]
+
+        dynamic "mount" {
+          for_each = ["/etc/api", "/var/lib/api"]
+          iterator = path
+
+          content {
+            type   = "bind"
+            source = "local${path.value}"
+            target = path.value
+          }
+        }
+      }
+
+      meta {
+        index = "${NOMAD_ALLOC_INDEX}"
+      }
+    }
+  }
+}
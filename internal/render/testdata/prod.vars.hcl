@@ -0,0 +1,2 @@
+image = "nginx"
+count = 3
@@ -6,6 +6,11 @@ import (
 	"github.com/zclconf/go-cty/cty"
 )
 
+// affinityOperators are operators accepted by Nomad, including aliases such as `==`
+var affinityOperators = []string{"=", "==", "is", "!=", "not", ">", ">=", "<", "<=", "regexp", "set_contains", "set_contains_all", "set_contains_any", "version", "semver"}
+
+var affinityOperatorValues = "`=`, `!=`, `>`, `>=`, `<`, `<=`, `regexp`, `set_contains_all`, `set_contains_any`, `version`, `semver`"
+
 var AffinitySchema = &schema.BodySchema{
 	Attributes: map[string]*schema.AttributeSchema{
 		"attribute": {
@@ -19,14 +24,23 @@ var AffinitySchema = &schema.BodySchema{
 			},
 			IsOptional: true,
 		},
-		// TODO: update docs
 		"operator": {
-			Description: lang.Markdown("Specifies the comparison operator. The ordering is compared lexically."),
+			Description: lang.Markdown("Specifies the comparison operator. The ordering is compared lexically.\n\nPossible values: " + affinityOperatorValues),
 			DefaultValue: schema.DefaultValue{
 				Value: cty.StringVal("="),
 			},
 			Constraint: schema.OneOf{
-				schema.LiteralType{Type: cty.String},
+				schema.LiteralValue{Value: cty.StringVal("=")},
+				schema.LiteralValue{Value: cty.StringVal("!=")},
+				schema.LiteralValue{Value: cty.StringVal(">")},
+				schema.LiteralValue{Value: cty.StringVal(">=")},
+				schema.LiteralValue{Value: cty.StringVal("<")},
+				schema.LiteralValue{Value: cty.StringVal("<=")},
+				schema.LiteralValue{Value: cty.StringVal("regexp")},
+				schema.LiteralValue{Value: cty.StringVal("set_contains_all")},
+				schema.LiteralValue{Value: cty.StringVal("set_contains_any")},
+				schema.LiteralValue{Value: cty.StringVal("version")},
+				schema.LiteralValue{Value: cty.StringVal("semver")},
 				schema.AnyExpression{OfType: cty.String},
 			},
 			IsOptional: true,
@@ -6,6 +6,9 @@ import (
 	"github.com/zclconf/go-cty/cty"
 )
 
+// constraintOperators are operators accepted by Nomad, including aliases such as `==`
+var constraintOperators = []string{"=", "==", "is", "!=", "not", ">", ">=", "<", "<=", "distinct_hosts", "distinct_property", "regexp", "set_contains", "set_contains_all", "set_contains_any", "version", "semver", "is_set", "is_not_set"}
+
 var operatorValues = "`=`, `!=`, `>`, `>=`, `<`, `<=`, `distinct_hosts`, `distinct_property`, `regexp`, `set_contains`, `set_contains_any`, `version`, `semver`, `is_set`, `is_not_set`"
 
 var ConstraintSchema = &schema.BodySchema{
@@ -33,6 +36,7 @@ var ConstraintSchema = &schema.BodySchema{
 				schema.LiteralValue{Value: cty.StringVal(">")},
 				schema.LiteralValue{Value: cty.StringVal(">=")},
 				schema.LiteralValue{Value: cty.StringVal("<")},
+				schema.LiteralValue{Value: cty.StringVal("<=")},
 				schema.LiteralValue{Value: cty.StringVal("distinct_hosts")},
 				schema.LiteralValue{Value: cty.StringVal("distinct_property")},
 				schema.LiteralValue{Value: cty.StringVal("regexp")},
@@ -0,0 +1,122 @@
+package job
+
+import (
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/schema/job/drivers"
+	schemautils "github.com/loczek/nomad-ls/internal/schemaUtils"
+)
+
+// BodyConstraints lists rules spanning multiple attributes of a body by its schema
+var BodyConstraints = schemautils.Constraints{
+	IdentitySchema: {
+		schemautils.RequiredWhen{
+			Attribute: "change_signal",
+			When:      schemautils.AttributeEquals{Name: "change_mode", Value: cty.StringVal("signal")},
+		},
+		schemautils.Duration{Attribute: "ttl"},
+	},
+	TemplateSchema: {
+		schemautils.RequiredWhen{
+			Attribute: "change_signal",
+			When:      schemautils.AttributeEquals{Name: "change_mode", Value: cty.StringVal("signal")},
+		},
+		schemautils.ExactlyOneOf{"source", "data"},
+		schemautils.Duration{Attribute: "splay"},
+	},
+	VolumeSchema: {
+		schemautils.RequiredWhen{
+			Attribute: "access_mode",
+			When:      schemautils.AttributeEquals{Name: "type", Value: cty.StringVal("csi")},
+		},
+		schemautils.RequiredWhen{
+			Attribute: "attachment_mode",
+			When:      schemautils.AttributeEquals{Name: "type", Value: cty.StringVal("csi")},
+		},
+	},
+	GroupSchema: {
+		schemautils.Duration{Attribute: "shutdown_delay"},
+	},
+	TaskSchema: {
+		schemautils.Duration{Attribute: "kill_timeout"},
+		schemautils.Duration{Attribute: "shutdown_delay"},
+	},
+	SidecarTaskSchema: {
+		schemautils.Duration{Attribute: "kill_timeout"},
+		schemautils.Duration{Attribute: "shutdown_delay"},
+	},
+	DisconnectSchema: {
+		schemautils.Duration{Attribute: "lost_after"},
+		schemautils.Duration{Attribute: "stop_on_client_after"},
+	},
+	UpdateSchema: {
+		schemautils.Duration{Attribute: "min_healthy_time"},
+		schemautils.Duration{Attribute: "healthy_deadline"},
+		schemautils.Duration{Attribute: "progress_deadline"},
+		schemautils.Duration{Attribute: "stagger"},
+		schemautils.DurationLessThan{Attribute: "min_healthy_time", Than: "healthy_deadline"},
+		schemautils.DurationLessThan{Attribute: "healthy_deadline", Than: "progress_deadline"},
+	},
+	MigrateSchema: {
+		schemautils.Duration{Attribute: "min_healthy_time"},
+		schemautils.Duration{Attribute: "healthy_deadline"},
+		schemautils.DurationLessThan{Attribute: "min_healthy_time", Than: "healthy_deadline"},
+	},
+	RestartSchema: {
+		schemautils.Duration{Attribute: "interval"},
+		schemautils.Duration{Attribute: "delay"},
+	},
+	RescheduleSchema: {
+		schemautils.Duration{Attribute: "interval"},
+		schemautils.Duration{Attribute: "delay"},
+		schemautils.Duration{Attribute: "max_delay"},
+		schemautils.DurationLessThan{Attribute: "delay", Than: "max_delay", OrEqual: true},
+	},
+	CheckSchema: {
+		schemautils.Duration{Attribute: "interval", Min: time.Second},
+		schemautils.Duration{Attribute: "timeout", Min: time.Second},
+		schemautils.DurationLessThan{Attribute: "timeout", Than: "interval"},
+	},
+	PeriodicSchema: {
+		schemautils.ExactlyOneOf{"cron", "crons"},
+		schemautils.Cron{Attribute: "cron", TimeZone: "time_zone", DefaultTimeZone: "UTC"},
+		schemautils.Cron{Attribute: "crons", TimeZone: "time_zone", DefaultTimeZone: "UTC"},
+		schemautils.TimeZone{Attribute: "time_zone"},
+	},
+	CronSchema: {
+		schemautils.Cron{Attribute: "start", TimeZone: "timezone", DefaultTimeZone: "Local", Restricted: true},
+		schemautils.CronTime{Attribute: "end"},
+		schemautils.TimeZone{Attribute: "timezone"},
+	},
+	ConstraintSchema: {
+		schemautils.Operator{
+			Operators:        constraintOperators,
+			Shorthands:       []string{"distinct_hosts", "distinct_property", "set_contains", "set_contains_any", "regexp", "version", "semver"},
+			RequireAttribute: true,
+		},
+	},
+	AffinitySchema: {
+		schemautils.Operator{
+			Operators:        affinityOperators,
+			RequireAttribute: true,
+		},
+	},
+	CheckRestartSchema: {
+		schemautils.Duration{Attribute: "grace"},
+	},
+	ChangeScriptSchema: {
+		schemautils.Duration{Attribute: "timeout"},
+	},
+	WaitSchema: {
+		schemautils.Duration{Attribute: "min"},
+		schemautils.Duration{Attribute: "max"},
+		schemautils.DurationLessThan{Attribute: "min", Than: "max", OrEqual: true},
+	},
+	drivers.DockerDriverSchema: {
+		schemautils.Duration{Attribute: "image_pull_timeout"},
+		schemautils.Size{Attribute: "shm_size", Unit: 1},
+		schemautils.Size{Attribute: "memory_hard_limit", Unit: 1 << 20},
+	},
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/loczek/nomad-ls/internal/schema/job/drivers"
+	"github.com/loczek/nomad-ls/internal/scope"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -118,6 +119,16 @@ var GroupSchema = &schema.BodySchema{
 			Body:        VaultSchema,
 		},
 		"volume": {
+			// targets are scoped to the enclosing group by [references.ScopeGroupTargets]
+			Address: &schema.BlockAddrSchema{
+				Steps: []schema.AddrStep{
+					schema.StaticStep{Name: "volume"},
+					schema.LabelStep{Index: 0},
+				},
+				FriendlyName: "volume",
+				ScopeId:      scope.VolumeScope,
+				AsReference:  true,
+			},
 			Description: lang.PlainText("Specifies the volumes that are required by tasks within the group."),
 			Body:        VolumeSchema,
 			Labels: []*schema.LabelSchema{
@@ -140,15 +151,17 @@ func attrKey(value string) schema.SchemaKey {
 	})
 }
 
+// createConfigSchema returns the part of the task body specific to a driver,
+// it is merged into [TaskSchema] so other blocks keep their schemas
 func createConfigSchema(innerBody *schema.BodySchema) *schema.BodySchema {
-	var innerTask = TaskSchema.Copy()
-
 	var driverConfig = &schema.BlockSchema{
 		Description: lang.PlainText("Specifies the driver configuration, which is passed directly to the driver to start the task. The details of configurations are specific to each driver, so please see specific driver documentation for more information."),
 		Body:        innerBody,
 	}
 
-	innerTask.Blocks["config"] = driverConfig
-
-	return innerTask
+	return &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"config": driverConfig,
+		},
+	}
 }
@@ -11,11 +11,10 @@ var LifecycleSchema = &schema.BodySchema{
 		// TODO: update docs
 		"hook": {
 			Description: lang.Markdown("Specifies when a task should be run within the lifecycle of a group. The following hooks are available:\n- `prestart` - Will be started immediately. The main tasks will not start until all prestart tasks with sidecar = false have completed successfully."),
-			DefaultValue: schema.DefaultValue{
-				Value: cty.StringVal("default"),
-			},
 			Constraint: schema.OneOf{
-				schema.LiteralType{Type: cty.String},
+				schema.LiteralValue{Value: cty.StringVal("prestart")},
+				schema.LiteralValue{Value: cty.StringVal("poststart")},
+				schema.LiteralValue{Value: cty.StringVal("poststop")},
 				schema.AnyExpression{OfType: cty.String},
 			},
 			IsRequired: true,
@@ -3,6 +3,7 @@ package job
 import (
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/loczek/nomad-ls/internal/scope"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -59,6 +60,16 @@ var NetworkSchema = &schema.BodySchema{
 	},
 	Blocks: map[string]*schema.BlockSchema{
 		"port": {
+			// targets are scoped to the enclosing group by [references.ScopeGroupTargets]
+			Address: &schema.BlockAddrSchema{
+				Steps: []schema.AddrStep{
+					schema.StaticStep{Name: "port"},
+					schema.LabelStep{Index: 0},
+				},
+				FriendlyName: "port",
+				ScopeId:      scope.PortScope,
+				AsReference:  true,
+			},
 			Description: lang.Markdown("Specifies a TCP/UDP port allocation and can be used to specify both dynamic ports and reserved ports."),
 			Body:        PortSchema,
 			Labels: []*schema.LabelSchema{
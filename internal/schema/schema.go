@@ -8,6 +8,7 @@ import (
 	nodePool "github.com/loczek/nomad-ls/internal/schema/node-pool"
 	resourceQuota "github.com/loczek/nomad-ls/internal/schema/resource-quota"
 	"github.com/loczek/nomad-ls/internal/schema/variable"
+	"github.com/loczek/nomad-ls/internal/schema/vars"
 	"github.com/loczek/nomad-ls/internal/schema/volume/csi"
 	"github.com/loczek/nomad-ls/internal/schema/volume/dynamic"
 )
@@ -21,3 +22,4 @@ var NomadNamespace = namespace.RootSchema
 var NomadNodePool = nodePool.RootSchema
 var NomadResourceQuota = resourceQuota.RootSchema
 var NomadVariable = variable.RootSchema
+var NomadVars = vars.RootSchema
@@ -0,0 +1,18 @@
+package vars
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// RootSchema accepts any variable, it is used when no job declaring
+// the variables is found next to the var file
+var RootSchema = &schema.BodySchema{
+	Description: lang.Markdown("Values of input variables passed to `nomad job run -var-file`"),
+	AnyAttribute: &schema.AttributeSchema{
+		Description: lang.Markdown("Value of the input variable"),
+		Constraint:  schema.LiteralType{Type: cty.DynamicPseudoType},
+		IsOptional:  true,
+	},
+}
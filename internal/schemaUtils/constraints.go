@@ -0,0 +1,127 @@
+package schemautils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// BodyConstraint is a rule spanning multiple attributes of a block body,
+// which can not be expressed by `IsRequired` of a single attribute
+type BodyConstraint interface {
+	Validate(body *hclsyntax.Body) hcl.Diagnostics
+}
+
+// Constraints attaches body constraints to the body schemas they apply to,
+// see [DeclaredBodies] for finding body schemas of a block
+type Constraints map[*schema.BodySchema][]BodyConstraint
+
+// Of returns constraints attached to any of the bodies
+func (c Constraints) Of(bodies []*schema.BodySchema) []BodyConstraint {
+	constraints := make([]BodyConstraint, 0)
+	for _, body := range bodies {
+		constraints = append(constraints, c[body]...)
+	}
+
+	return constraints
+}
+
+// AttributeEquals holds when the attribute is statically set to Value
+type AttributeEquals struct {
+	Name  string
+	Value cty.Value
+}
+
+func (c AttributeEquals) holds(body *hclsyntax.Body) bool {
+	attr, ok := body.Attributes[c.Name]
+	if !ok {
+		return false
+	}
+
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || !val.Type().Equals(c.Value.Type()) {
+		return false
+	}
+
+	return val.Equals(c.Value).True()
+}
+
+func (c AttributeEquals) String() string {
+	if c.Value.Type() == cty.String {
+		return fmt.Sprintf("`%s` is %q", c.Name, c.Value.AsString())
+	}
+
+	return fmt.Sprintf("`%s` is `%s`", c.Name, c.Value.GoString())
+}
+
+// RequiredWhen requires the attribute when the condition holds,
+// e.g. `change_signal` when `change_mode = "signal"`
+type RequiredWhen struct {
+	Attribute string
+	When      AttributeEquals
+}
+
+func (c RequiredWhen) Validate(body *hclsyntax.Body) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	if _, ok := body.Attributes[c.Attribute]; ok || !c.When.holds(body) {
+		return diags
+	}
+
+	// the summary matches the one of hcl-lang so that the quick fix applies
+	return append(diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("Required attribute %q not specified", c.Attribute),
+		Detail:   fmt.Sprintf("An attribute named %q is required when %s", c.Attribute, c.When),
+		Subject:  body.SrcRange.Ptr(),
+	})
+}
+
+// ExactlyOneOf requires exactly one of the attributes to be set
+type ExactlyOneOf []string
+
+func (c ExactlyOneOf) Validate(body *hclsyntax.Body) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	set := make([]*hclsyntax.Attribute, 0)
+	for _, name := range c {
+		if attr, ok := body.Attributes[name]; ok {
+			set = append(set, attr)
+		}
+	}
+
+	switch len(set) {
+	case 1:
+		return diags
+	case 0:
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("One of %s must be specified", c.names()),
+			Subject:  body.SrcRange.Ptr(),
+		})
+	}
+
+	for _, attr := range set[1:] {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Only one of %s can be specified", c.names()),
+			Detail:   fmt.Sprintf("An attribute named %q conflicts with %q", attr.Name, set[0].Name),
+			Subject:  attr.SrcRange.Ptr(),
+		})
+	}
+
+	return diags
+}
+
+func (c ExactlyOneOf) names() string {
+	quoted := make([]string, 0, len(c))
+	for _, name := range c {
+		quoted = append(quoted, fmt.Sprintf("%q", name))
+	}
+
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,218 @@
+package schemautils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	// time zones are validated against the embedded database
+	// so that results do not depend on the zoneinfo of the host
+	_ "time/tzdata"
+
+	"github.com/hashicorp/cronexpr"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/loczek/nomad-ls/internal/references"
+)
+
+// nextLaunches is the number of fire times described on hover
+const nextLaunches = 5
+
+// now returns the current time, tests replace it to get stable launches
+var now = time.Now
+
+// Cron requires the attribute to be a cron expression or a list of them,
+// launch times are described in the time zone set by the TimeZone attribute
+type Cron struct {
+	Attribute       string
+	TimeZone        string
+	DefaultTimeZone string
+	// Restricted forbids `,` and `/` characters, e.g. in `schedule` blocks
+	Restricted bool
+}
+
+func (c Cron) Validate(body *hclsyntax.Body) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	attr, ok := body.Attributes[c.Attribute]
+	if !ok {
+		return diags
+	}
+
+	for _, expr := range references.ElementExprs(attr.Expr) {
+		spec, ok := nonEmptyString(expr)
+		if !ok {
+			continue
+		}
+
+		if c.Restricted && strings.ContainsAny(spec, ",/") {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid cron expression",
+				Detail:   fmt.Sprintf("%q can not contain `,` or `/` characters", spec),
+				Subject:  expr.Range().Ptr(),
+			})
+			continue
+		}
+
+		cron, err := cronexpr.Parse(spec)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid cron expression",
+				Detail:   fmt.Sprintf("%q is not a valid cron expression: %s", spec, err),
+				Subject:  expr.Range().Ptr(),
+			})
+			continue
+		}
+
+		if cron.Next(now()).IsZero() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Cron expression never fires",
+				Detail:   fmt.Sprintf("%q does not match any time in the future", spec),
+				Subject:  expr.Range().Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
+// Describe lists the next launches of all expressions of the attribute
+func (c Cron) Describe(body *hclsyntax.Body, attr *hclsyntax.Attribute) (string, bool) {
+	if attr.Name != c.Attribute {
+		return "", false
+	}
+
+	loc, ok := c.location(body)
+	if !ok {
+		return "", false
+	}
+
+	from := now().In(loc)
+	launches := make([]time.Time, 0)
+	for _, expr := range references.ElementExprs(attr.Expr) {
+		spec, ok := nonEmptyString(expr)
+		if !ok {
+			continue
+		}
+
+		cron, err := cronexpr.Parse(spec)
+		if err != nil {
+			continue
+		}
+
+		launches = append(launches, cron.NextN(from, nextLaunches)...)
+	}
+
+	if len(launches) == 0 {
+		return "", false
+	}
+
+	launches = earliest(launches, nextLaunches)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Next launches (%s):\n", loc)
+	for _, t := range launches {
+		fmt.Fprintf(&sb, "- `%s`\n", t.Format("Mon, 02 Jan 2006 15:04 MST"))
+	}
+
+	return sb.String(), true
+}
+
+func (c Cron) location(body *hclsyntax.Body) (*time.Location, bool) {
+	name := c.DefaultTimeZone
+	if attr, ok := body.Attributes[c.TimeZone]; ok {
+		if name, ok = nonEmptyString(attr.Expr); !ok {
+			return nil, false
+		}
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+
+	return loc, true
+}
+
+// CronTime requires the attribute to be a time of day in the
+// 2 field cron format (minute and hour), e.g. `end = "0 16"`
+type CronTime struct {
+	Attribute string
+}
+
+func (c CronTime) Validate(body *hclsyntax.Body) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	attr, ok := body.Attributes[c.Attribute]
+	if !ok {
+		return diags
+	}
+
+	spec, ok := nonEmptyString(attr.Expr)
+	if !ok {
+		return diags
+	}
+
+	var err error
+	switch {
+	case len(strings.Fields(spec)) != 2:
+		err = fmt.Errorf("expected 2 fields (minute and hour), found %d", len(strings.Fields(spec)))
+	case strings.ContainsAny(spec, ",/"):
+		err = fmt.Errorf("can not contain `,` or `/` characters")
+	default:
+		_, err = cronexpr.Parse(spec + " * * *")
+	}
+
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid cron expression",
+			Detail:   fmt.Sprintf("%q is not a valid time of day: %s", spec, err),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+
+	return diags
+}
+
+// TimeZone requires the attribute to be a name of the IANA time zone database
+type TimeZone struct {
+	Attribute string
+}
+
+func (c TimeZone) Validate(body *hclsyntax.Body) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	attr, ok := body.Attributes[c.Attribute]
+	if !ok {
+		return diags
+	}
+
+	name, ok := nonEmptyString(attr.Expr)
+	if !ok {
+		return diags
+	}
+
+	if _, err := time.LoadLocation(name); err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid time zone",
+			Detail:   fmt.Sprintf("%q is not a time zone of the IANA time zone database, e.g. \"America/New_York\"", name),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+
+	return diags
+}
+
+// earliest returns first n times in order
+func earliest(times []time.Time, n int) []time.Time {
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+	times = slices.CompactFunc(times, time.Time.Equal)
+
+	return times[:min(n, len(times))]
+}
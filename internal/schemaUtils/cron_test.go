@@ -0,0 +1,65 @@
+package schemautils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// setNow fixes the current time until the test ends
+func setNow(t *testing.T, fixed time.Time) {
+	t.Cleanup(func() { now = time.Now })
+	now = func() time.Time { return fixed }
+}
+
+func parseCronBody(t *testing.T, src string) *hclsyntax.Body {
+	file, diags := hclsyntax.ParseConfig([]byte(src), "test.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	return file.Body.(*hclsyntax.Body)
+}
+
+func TestCronDescribe(t *testing.T) {
+	setNow(t, time.Date(2026, time.March, 27, 12, 0, 0, 0, time.UTC))
+
+	body := parseCronBody(t, `crons     = ["0 9 * * 1", "30 8 28 3 *"]
+time_zone = "Europe/Warsaw"
+`)
+	constraint := Cron{Attribute: "crons", TimeZone: "time_zone", DefaultTimeZone: "UTC"}
+
+	description, ok := constraint.Describe(body, body.Attributes["crons"])
+	if !ok {
+		t.Fatal("expected launches to be described")
+	}
+
+	// the clocks move forward on the 29th of March
+	expected := "Next launches (Europe/Warsaw):\n" +
+		"- `Sat, 28 Mar 2026 08:30 CET`\n" +
+		"- `Mon, 30 Mar 2026 09:00 CEST`\n" +
+		"- `Mon, 06 Apr 2026 09:00 CEST`\n" +
+		"- `Mon, 13 Apr 2026 09:00 CEST`\n" +
+		"- `Mon, 20 Apr 2026 09:00 CEST`\n"
+
+	if description != expected {
+		t.Errorf("expected: %q, recieved: %q", expected, description)
+	}
+}
+
+func TestCronNeverFires(t *testing.T) {
+	body := parseCronBody(t, `crons = ["0 0 1 1 * 2025"]`)
+	constraint := Cron{Attribute: "crons"}
+
+	setNow(t, time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC))
+	if diags := constraint.Validate(body); len(diags) != 0 {
+		t.Errorf("expected the expression to fire in 2025, recieved: %v", diags)
+	}
+
+	setNow(t, time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC))
+	if diags := constraint.Validate(body); len(diags) != 1 || diags[0].Summary != "Cron expression never fires" {
+		t.Errorf("expected the expression to never fire, recieved: %v", diags)
+	}
+}
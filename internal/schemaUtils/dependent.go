@@ -0,0 +1,109 @@
+package schemautils
+
+import (
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// BlockBodySchema returns body schema of the block merged with any dependent body
+// matching the block labels or static string attributes (e.g. task `driver`)
+func BlockBodySchema(block *hclsyntax.Block, blockSchema *schema.BlockSchema) *schema.BodySchema {
+	bodySchema := blockSchema.Body
+
+	for _, key := range dependencyKeys(block) {
+		depBody, ok := blockSchema.DependentBody[key]
+		if !ok {
+			continue
+		}
+
+		bodySchema = mergeBodySchemas(bodySchema, depBody)
+	}
+
+	return bodySchema
+}
+
+// DeclaredBodies returns body schemas of the block as declared in one of the parent bodies,
+// the body and any dependent body matching the block. hcl-lang validates blocks against
+// merged copies, these are the schemas themselves so they can be looked up by identity
+func DeclaredBodies(block *hclsyntax.Block, parents []*schema.BodySchema) []*schema.BodySchema {
+	var blockSchema *schema.BlockSchema
+	for _, parent := range parents {
+		if bs, ok := parent.Blocks[block.Type]; ok {
+			blockSchema = bs
+			break
+		}
+	}
+
+	if blockSchema == nil {
+		return nil
+	}
+
+	// dependent bodies come first as they take precedence when merged
+	bodies := make([]*schema.BodySchema, 0)
+	for _, key := range dependencyKeys(block) {
+		if depBody, ok := blockSchema.DependentBody[key]; ok {
+			bodies = append(bodies, depBody)
+		}
+	}
+
+	if blockSchema.Body != nil {
+		bodies = append(bodies, blockSchema.Body)
+	}
+
+	return bodies
+}
+
+func dependencyKeys(block *hclsyntax.Block) []schema.SchemaKey {
+	keys := make([]schema.SchemaKey, 0)
+
+	for i, label := range block.Labels {
+		keys = append(keys, schema.NewSchemaKey(schema.DependencyKeys{
+			Labels: []schema.LabelDependent{
+				{Index: i, Value: label},
+			},
+		}))
+	}
+
+	for name, attr := range block.Body.Attributes {
+		val, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || val.Type() != cty.String {
+			continue
+		}
+
+		keys = append(keys, schema.NewSchemaKey(schema.DependencyKeys{
+			Attributes: []schema.AttributeDependent{
+				{
+					Name: name,
+					Expr: schema.ExpressionValue{Static: val},
+				},
+			},
+		}))
+	}
+
+	return keys
+}
+
+func mergeBodySchemas(base *schema.BodySchema, other *schema.BodySchema) *schema.BodySchema {
+	if base == nil {
+		return other
+	}
+
+	merged := base.Copy()
+	if merged.Attributes == nil {
+		merged.Attributes = make(map[string]*schema.AttributeSchema)
+	}
+	if merged.Blocks == nil {
+		merged.Blocks = make(map[string]*schema.BlockSchema)
+	}
+
+	for name, attr := range other.Attributes {
+		merged.Attributes[name] = attr
+	}
+
+	for name, block := range other.Blocks {
+		merged.Blocks[name] = block
+	}
+
+	return merged
+}
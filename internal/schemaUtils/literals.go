@@ -0,0 +1,265 @@
+package schemautils
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+)
+
+// ValueDescriber is implemented by constraints which can describe
+// a static value of an attribute, e.g. the normalised duration on hover
+type ValueDescriber interface {
+	Describe(body *hclsyntax.Body, attr *hclsyntax.Attribute) (string, bool)
+}
+
+var (
+	_ ValueDescriber = Duration{}
+	_ ValueDescriber = Size{}
+	_ ValueDescriber = Cron{}
+)
+
+// Duration requires the attribute to be a duration string like "30s" or "1h30m"
+// or a number of nanoseconds
+type Duration struct {
+	Attribute string
+	// Min is the shortest allowed duration, zero when not limited
+	Min time.Duration
+}
+
+func (c Duration) Validate(body *hclsyntax.Body) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	attr, ok := body.Attributes[c.Attribute]
+	if !ok {
+		return diags
+	}
+
+	d, ok := nanoseconds(attr.Expr)
+	if !ok {
+		raw, ok := nonEmptyString(attr.Expr)
+		if !ok {
+			return diags
+		}
+
+		var err error
+		if d, err = time.ParseDuration(raw); err != nil {
+			return append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid duration",
+				Detail:   fmt.Sprintf("%q is not a valid duration, expected numbers with unit suffixes like \"30s\" or \"1h30m\"", raw),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	if c.Min > 0 && d < c.Min {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Duration too short",
+			Detail:   fmt.Sprintf("An attribute named %q must be at least %s", c.Attribute, c.Min),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+
+	return diags
+}
+
+func (c Duration) Describe(body *hclsyntax.Body, attr *hclsyntax.Attribute) (string, bool) {
+	if attr.Name != c.Attribute {
+		return "", false
+	}
+
+	if d, ok := nanoseconds(attr.Expr); ok {
+		return fmt.Sprintf("`%d` nanoseconds = `%s`", d.Nanoseconds(), d), true
+	}
+
+	raw, ok := nonEmptyString(attr.Expr)
+	if !ok {
+		return "", false
+	}
+
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return "", false
+	}
+
+	if d.String() == raw {
+		return fmt.Sprintf("`%s` = %s seconds", raw, strconv.FormatFloat(d.Seconds(), 'f', -1, 64)), true
+	}
+
+	return fmt.Sprintf("`%s` = `%s`", raw, d), true
+}
+
+// DurationLessThan requires the duration of Attribute to be less than the one of Than,
+// unset, invalid and zero durations are ignored as zero usually disables the setting
+type DurationLessThan struct {
+	Attribute string
+	Than      string
+	OrEqual   bool
+}
+
+func (c DurationLessThan) Validate(body *hclsyntax.Body) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	attr, ok := body.Attributes[c.Attribute]
+	if !ok {
+		return diags
+	}
+
+	d, ok := staticDuration(attr)
+	if !ok {
+		return diags
+	}
+
+	than, ok := staticDuration(body.Attributes[c.Than])
+	if !ok {
+		return diags
+	}
+
+	if d < than || (c.OrEqual && d == than) {
+		return diags
+	}
+
+	relation := "less than"
+	if c.OrEqual {
+		relation = "less than or equal to"
+	}
+
+	return append(diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("%q must be %s %q", c.Attribute, relation, c.Than),
+		Detail:   fmt.Sprintf("An attribute named %q (%s) must be %s %q (%s)", c.Attribute, d, relation, c.Than, than),
+		Subject:  attr.Expr.Range().Ptr(),
+	})
+}
+
+// Size requires the attribute to be a whole number of Unit bytes,
+// e.g. megabytes for docker `memory_hard_limit`
+type Size struct {
+	Attribute string
+	Unit      int64
+}
+
+func (c Size) Validate(body *hclsyntax.Body) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	attr, ok := body.Attributes[c.Attribute]
+	if !ok {
+		return diags
+	}
+
+	raw, ok := nonEmptyString(attr.Expr)
+	if !ok {
+		return diags
+	}
+
+	if _, err := strconv.ParseUint(raw, 10, 63); err != nil {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid size",
+			Detail:   fmt.Sprintf("%q is not a valid size, expected a whole number of %s", raw, unitName(c.Unit)),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+
+	return diags
+}
+
+func (c Size) Describe(body *hclsyntax.Body, attr *hclsyntax.Attribute) (string, bool) {
+	if attr.Name != c.Attribute {
+		return "", false
+	}
+
+	raw, ok := nonEmptyString(attr.Expr)
+	if !ok {
+		return "", false
+	}
+
+	n, err := strconv.ParseUint(raw, 10, 63)
+	if err != nil {
+		return "", false
+	}
+
+	return fmt.Sprintf("`%s` = `%s`", raw, FormatBytes(float64(n)*float64(c.Unit))), true
+}
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+
+// FormatBytes formats a number of bytes using the largest binary unit, e.g. `1.5 GiB`
+func FormatBytes(n float64) string {
+	i := 0
+	for n >= 1024 && i < len(byteUnits)-1 {
+		n /= 1024
+		i++
+	}
+
+	return fmt.Sprintf("%s %s", strconv.FormatFloat(math.Round(n*100)/100, 'f', -1, 64), byteUnits[i])
+}
+
+func unitName(unit int64) string {
+	switch unit {
+	case 1:
+		return "bytes"
+	case 1 << 20:
+		return "megabytes"
+	}
+
+	return FormatBytes(float64(unit)) + " units"
+}
+
+func staticDuration(attr *hclsyntax.Attribute) (time.Duration, bool) {
+	if attr == nil {
+		return 0, false
+	}
+
+	if d, ok := nanoseconds(attr.Expr); ok {
+		return d, d != 0
+	}
+
+	raw, ok := nonEmptyString(attr.Expr)
+	if !ok {
+		return 0, false
+	}
+
+	d, err := time.ParseDuration(raw)
+	if err != nil || d == 0 {
+		return 0, false
+	}
+
+	return d, true
+}
+
+// nanoseconds returns the duration of a number literal,
+// Nomad accepts numbers of nanoseconds in place of duration strings
+func nanoseconds(expr hclsyntax.Expression) (time.Duration, bool) {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || val.Type() != cty.Number {
+		return 0, false
+	}
+
+	n, acc := val.AsBigFloat().Int64()
+	if acc != big.Exact {
+		return 0, false
+	}
+
+	return time.Duration(n), true
+}
+
+// nonEmptyString returns the value of a non-empty string literal,
+// an empty string is left to the schema as it means the attribute is unset
+func nonEmptyString(expr hclsyntax.Expression) (string, bool) {
+	raw, ok := eval.StaticString(expr)
+	if !ok || raw == "" {
+		return "", false
+	}
+
+	return raw, true
+}
@@ -0,0 +1,80 @@
+package schemautils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	schemautils "github.com/loczek/nomad-ls/internal/schemaUtils"
+)
+
+func parseBody(t *testing.T, src string) *hclsyntax.Body {
+	file, diags := hclsyntax.ParseConfig([]byte(src), "test.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	return file.Body.(*hclsyntax.Body)
+}
+
+func TestDuration(t *testing.T) {
+	tests := map[string]struct {
+		src     string
+		summary string
+	}{
+		"string":            {`kill_timeout = "30s"`, ""},
+		"nanoseconds":       {`kill_timeout = 5000000000`, ""},
+		"missing unit":      {`kill_timeout = "30"`, "Invalid duration"},
+		"short string":      {`kill_timeout = "10ms"`, "Duration too short"},
+		"short nanoseconds": {`kill_timeout = 1000`, "Duration too short"},
+	}
+
+	constraint := schemautils.Duration{Attribute: "kill_timeout", Min: time.Second}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			diags := constraint.Validate(parseBody(t, test.src))
+
+			if test.summary == "" {
+				if len(diags) != 0 {
+					t.Errorf("expected no diagnostics, recieved: %v", diags)
+				}
+				return
+			}
+
+			if len(diags) != 1 || diags[0].Summary != test.summary {
+				t.Errorf("expected %q, recieved: %v", test.summary, diags)
+			}
+		})
+	}
+}
+
+func TestDurationLessThanNanoseconds(t *testing.T) {
+	body := parseBody(t, "interval = \"10s\"\ntimeout = 20000000000\n")
+
+	diags := schemautils.DurationLessThan{Attribute: "timeout", Than: "interval"}.Validate(body)
+	if len(diags) != 1 {
+		t.Errorf("expected timeout of 20s not to be less than 10s, recieved: %v", diags)
+	}
+}
+
+func TestDurationDescribe(t *testing.T) {
+	tests := map[string]string{
+		`kill_timeout = "90s"`:      "`90s` = `1m30s`",
+		`kill_timeout = "5s"`:       "`5s` = 5 seconds",
+		`kill_timeout = 5000000000`: "`5000000000` nanoseconds = `5s`",
+	}
+
+	constraint := schemautils.Duration{Attribute: "kill_timeout"}
+
+	for src, expected := range tests {
+		body := parseBody(t, src)
+
+		desc, ok := constraint.Describe(body, body.Attributes["kill_timeout"])
+		if !ok || desc != expected {
+			t.Errorf("expected %q, recieved: %q", expected, desc)
+		}
+	}
+}
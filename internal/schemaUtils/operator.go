@@ -0,0 +1,220 @@
+package schemautils
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// valueUsage describes how an operator uses the `value` attribute
+type valueUsage int
+
+const (
+	valueRequired valueUsage = iota
+	valueOptional
+	valueUnused
+)
+
+type operatorRule struct {
+	value valueUsage
+	// attributeUnused operators do not examine any `attribute`
+	attributeUnused bool
+	// check validates the static value
+	check func(value string) error
+}
+
+// operatorRules follow the validation of Nomad constraints
+var operatorRules = map[string]operatorRule{
+	"=":                 {},
+	"==":                {},
+	"is":                {},
+	"!=":                {},
+	"not":               {},
+	">":                 {},
+	">=":                {},
+	"<":                 {},
+	"<=":                {},
+	"distinct_hosts":    {value: valueOptional, attributeUnused: true},
+	"distinct_property": {value: valueOptional, check: checkCount},
+	"regexp":            {check: checkRegexp},
+	"set_contains":      {},
+	"set_contains_all":  {},
+	"set_contains_any":  {},
+	"version":           {check: checkVersion},
+	"semver":            {check: checkSemver},
+	"is_set":            {value: valueUnused},
+	"is_not_set":        {value: valueUnused},
+}
+
+// Operator validates `operator` of placement blocks (`constraint`, `affinity`)
+// together with the `attribute` and `value` it is applied to
+type Operator struct {
+	// Operators are the allowed operators
+	Operators []string
+	// Shorthands are attributes setting both the operator and the value,
+	// e.g. `regexp = "linux|darwin"` in `constraint` blocks
+	Shorthands []string
+	// RequireAttribute reports missing `attribute` for operators examining one
+	RequireAttribute bool
+}
+
+func (c Operator) Validate(body *hclsyntax.Body) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	operator := "="
+	operatorAttr, hasOperator := body.Attributes["operator"]
+	valueAttr, hasValue := body.Attributes["value"]
+
+	shorthands := make([]*hclsyntax.Attribute, 0)
+	for _, name := range c.Shorthands {
+		if attr, ok := body.Attributes[name]; ok {
+			shorthands = append(shorthands, attr)
+		}
+	}
+
+	switch {
+	case len(shorthands) > 1 || (len(shorthands) == 1 && hasOperator):
+		for _, attr := range shorthands[1:] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Conflicting operators",
+				Detail:   fmt.Sprintf("An attribute named %q conflicts with %q, only one operator can be specified", attr.Name, shorthands[0].Name),
+				Subject:  attr.SrcRange.Ptr(),
+			})
+		}
+
+		if hasOperator {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Conflicting operators",
+				Detail:   fmt.Sprintf("`operator` should be omitted when using the %q shorthand", shorthands[0].Name),
+				Subject:  operatorAttr.SrcRange.Ptr(),
+			})
+		}
+
+		return diags
+	case len(shorthands) == 1:
+		operator = shorthands[0].Name
+		operatorAttr = shorthands[0]
+		hasOperator = true
+
+		switch operator {
+		case "distinct_hosts":
+			// the shorthand is a bool
+		case "distinct_property":
+			// the shorthand replaces `attribute`
+		default:
+			valueAttr, hasValue = shorthands[0], true
+		}
+	case hasOperator:
+		var ok bool
+		if operator, ok = nonEmptyString(operatorAttr.Expr); !ok {
+			return diags
+		}
+	}
+
+	rule, ok := operatorRules[operator]
+	if !ok || !slices.Contains(c.Operators, operator) {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Unknown operator %q", operator),
+			Detail:   fmt.Sprintf("Possible operators: %s", quoteAll(c.Operators)),
+			Subject:  operatorAttr.Expr.Range().Ptr(),
+		})
+	}
+
+	_, hasAttribute := body.Attributes["attribute"]
+	if operator == "distinct_property" && len(shorthands) == 1 {
+		hasAttribute = true
+	}
+
+	if c.RequireAttribute && !rule.attributeUnused && !hasAttribute {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  `Required attribute "attribute" not specified`,
+			Detail:   fmt.Sprintf("An attribute named %q is required by the %q operator", "attribute", operator),
+			Subject:  body.SrcRange.Ptr(),
+		})
+	}
+
+	switch {
+	case !hasValue && rule.value == valueRequired:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  `Required attribute "value" not specified`,
+			Detail:   fmt.Sprintf("An attribute named %q is required by the %q operator", "value", operator),
+			Subject:  body.SrcRange.Ptr(),
+		})
+	case hasValue && rule.value == valueUnused:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unexpected value",
+			Detail:   fmt.Sprintf("The %q operator does not use `value`", operator),
+			Subject:  valueAttr.SrcRange.Ptr(),
+		})
+	case hasValue && rule.check != nil:
+		value, ok := nonEmptyString(valueAttr.Expr)
+		if !ok {
+			break
+		}
+
+		if err := rule.check(value); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Invalid value for %q operator", operator),
+				Detail:   fmt.Sprintf("%q is not valid, %s", value, err),
+				Subject:  valueAttr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
+func checkRegexp(value string) error {
+	if _, err := regexp.Compile(value); err != nil {
+		return fmt.Errorf("regular expression failed to compile: %w", err)
+	}
+
+	return nil
+}
+
+func checkVersion(value string) error {
+	if _, err := version.NewConstraint(value); err != nil {
+		return fmt.Errorf("version constraint is invalid: %w", err)
+	}
+
+	return nil
+}
+
+// checkSemver rejects the pessimistic operator which is only supported by `version`
+func checkSemver(value string) error {
+	if strings.Contains(value, "~>") {
+		return fmt.Errorf("the pessimistic operator `~>` is not supported, use the `version` operator instead")
+	}
+
+	return checkVersion(value)
+}
+
+func checkCount(value string) error {
+	if n, err := strconv.ParseUint(value, 10, 64); err != nil || n < 1 {
+		return fmt.Errorf("expected a number greater than or equal to 1")
+	}
+
+	return nil
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("`%s`", v))
+	}
+
+	return strings.Join(quoted, ", ")
+}
@@ -12,4 +12,6 @@ var (
 	ListScope     = lang.ScopeId("list")
 	ActionScope   = lang.ScopeId("action")
 	MetaScope     = lang.ScopeId("meta")
+	PortScope     = lang.ScopeId("port")
+	VolumeScope   = lang.ScopeId("volume")
 )
@@ -2,6 +2,7 @@ package store
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/reference"
@@ -11,35 +12,91 @@ import (
 	"github.com/loczek/nomad-ls/internal/references"
 )
 
-// Document represents a open file in memory
+// Document represents a open file in memory,
+// handlers and the workspace indexer access it concurrently
 type Document struct {
-	HCLFile *hcl.File
+	Language languages.LanguageID
 
-	RefTargets reference.Targets
-	RefOrigins reference.Origins
+	// fields below are guarded by mu
+	hclFile    *hcl.File
+	refTargets reference.Targets
+	refOrigins reference.Origins
+	version    int32
 
-	Language languages.LanguageID
-	Version  int32
+	// open is set for documents opened in the editor,
+	// their content takes precedence over the one on disk
+	open atomic.Bool
+
+	// parseErrors is set when the last parse reported errors,
+	// such documents are parsed again as a whole on edits
+	parseErrors bool
 
 	mu sync.Mutex
 }
 
 func NewDocument(language languages.LanguageID) *Document {
 	return &Document{
-		HCLFile:    &hcl.File{},
-		RefTargets: make(reference.Targets, 0),
-		RefOrigins: make(reference.Origins, 0),
+		hclFile:    &hcl.File{},
+		refTargets: make(reference.Targets, 0),
+		refOrigins: make(reference.Origins, 0),
 		Language:   language,
 		mu:         sync.Mutex{},
 	}
 }
 
+// IsOpen reports whether the document is opened in the editor
+func (f *Document) IsOpen() bool {
+	return f.open.Load()
+}
+
+func (f *Document) SetOpen(open bool) {
+	f.open.Store(open)
+}
+
+// File returns the last parsed file, files are never modified once parsed
+func (f *Document) File() *hcl.File {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.hclFile
+}
+
+func (f *Document) RefTargets() reference.Targets {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.refTargets
+}
+
+func (f *Document) RefOrigins() reference.Origins {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.refOrigins
+}
+
+// Version returns the version of the document in the editor
+func (f *Document) Version() int32 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.version
+}
+
+func (f *Document) SetVersion(version int32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.version = version
+}
+
 func (f *Document) ParseHCL(src []byte, filename string) (*hcl.File, hcl.Diagnostics) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
-	f.HCLFile = file
+	f.hclFile = file
+	f.parseErrors = diags.HasErrors()
 
 	return file, diags
 }
@@ -49,25 +106,83 @@ func (f *Document) UpdateHCL(src []byte, filename string) (*hcl.File, hcl.Diagno
 	defer f.mu.Unlock()
 
 	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
-	f.HCLFile = file
+	f.hclFile = file
+	f.parseErrors = diags.HasErrors()
 
 	return file, diags
 }
 
+// Edit returns the range of bytes of src replaced by text
+type Edit func(src []byte) (start, end int, text []byte)
+
+// EditHCL applies edits of a version of the document in order, only top-level attributes
+// and blocks touched by a single edit are parsed again when possible. Edits of versions
+// older than the one of the document are dropped, ok is false then
+func (f *Document) EditHCL(version int32, edits []Edit, filename string) (file *hcl.File, diags hcl.Diagnostics, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if version < f.version {
+		return f.hclFile, nil, false
+	}
+	f.version = version
+
+	if len(edits) == 1 && !f.parseErrors {
+		start, end, text := edits[0](f.hclFile.Bytes)
+		if file, diags, ok := reparse(f.hclFile, start, end, text, filename); ok {
+			f.hclFile = file
+
+			return file, diags, true
+		}
+	}
+
+	src := f.hclFile.Bytes
+	for _, edit := range edits {
+		start, end, text := edit(src)
+		src = replaceBytes(src, start, end, text)
+	}
+
+	file, diags = hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	f.hclFile = file
+	f.parseErrors = diags.HasErrors()
+
+	return file, diags, true
+}
+
+// UpdateReferences collects reference targets and origins of the last parsed file,
+// the decoder reads the document so it is locked only to store them
 func (f *Document) UpdateReferences(pathDecoder *decoder.PathDecoder, fileName string) error {
+	file := f.File()
+
 	targets, err := pathDecoder.CollectReferenceTargets()
 	if err != nil {
 		return err
 	}
 
-	f.RefTargets = append(targets, references.CommonBuiltinReferences()...)
+	if body, ok := file.Body.(*hclsyntax.Body); ok {
+		targets = references.ScopeGroupTargets(targets, body)
+	}
+
+	targets = append(targets, references.CommonBuiltinReferences()...)
+
+	if body, ok := file.Body.(*hclsyntax.Body); ok && f.Language == languages.NomadJob {
+		targets = append(targets, references.RuntimeEnvTargets(body)...)
+	}
 
 	origins, err := pathDecoder.CollectReferenceOrigins()
 	if err != nil {
 		return err
 	}
 
-	f.RefOrigins = origins
+	if body, ok := file.Body.(*hclsyntax.Body); ok {
+		origins = append(origins, references.LabelOrigins(body)...)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.refTargets = targets
+	f.refOrigins = origins
 
 	return nil
 }
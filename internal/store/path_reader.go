@@ -3,13 +3,18 @@ package store
 import (
 	"context"
 	"errors"
+	"slices"
 
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/validator"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	funcs "github.com/loczek/nomad-ls/internal/function"
 	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/schema/job"
+	custom_validators "github.com/loczek/nomad-ls/internal/validators"
+	"github.com/loczek/nomad-ls/internal/variables"
 )
 
 var _ decoder.PathReader = (*Store)(nil)
@@ -19,36 +24,88 @@ func (p *Store) PathContext(path lang.Path) (*decoder.PathContext, error) {
 	langID := languages.LanguageID(path.LanguageID)
 	langSchema := languages.ToSchema(langID)
 
+	var declared []variables.Variable
+	if langID == languages.NomadVars {
+		declared = p.siblingVariables(path.Path)
+		langSchema = *variables.Schema(declared)
+	}
+
+	p.mu.RLock()
 	file, ok := p.files[path.Path]
+	p.mu.RUnlock()
 	if !ok {
 		return nil, errors.New("file not found")
 	}
 
+	validators := []validator.Validator{
+		validator.BlockLabelsLength{},
+		validator.DeprecatedAttribute{},
+		validator.DeprecatedBlock{},
+		validator.MaxBlocks{},
+		validator.MinBlocks{},
+		validator.MissingRequiredAttribute{},
+		validator.UnexpectedAttribute{},
+		validator.UnexpectedBlock{},
+		custom_validators.PortLabel{},
+		custom_validators.VolumeMount{},
+		custom_validators.GroupLifecycle{},
+	}
+
+	if langID == languages.NomadJob {
+		validators = append(validators,
+			custom_validators.BodyConstraints{Schema: job.RootSchema, Constraints: job.BodyConstraints},
+			custom_validators.VariableValidation{},
+			custom_validators.FunctionCall{},
+		)
+	}
+
+	if langID == languages.NomadVars {
+		validators = append(validators, custom_validators.VariableValue{Variables: declared})
+	}
+
+	targets := file.RefTargets()
+	if langID == languages.NomadJob {
+		targets = append(slices.Clip(targets), p.NodeTargets()...)
+	}
+
 	return &decoder.PathContext{
 		Schema:           &langSchema,
-		ReferenceOrigins: file.RefOrigins,
-		ReferenceTargets: file.RefTargets,
+		ReferenceOrigins: file.RefOrigins(),
+		ReferenceTargets: targets,
 		Files: map[string]*hcl.File{
-			path.Path: file.HCLFile,
-		},
-		Functions: funcs.Functions,
-		Validators: []validator.Validator{
-			validator.BlockLabelsLength{},
-			validator.DeprecatedAttribute{},
-			validator.DeprecatedBlock{},
-			validator.MaxBlocks{},
-			validator.MinBlocks{},
-			validator.MissingRequiredAttribute{},
-			validator.UnexpectedAttribute{},
-			validator.UnexpectedBlock{},
+			path.Path: file.File(),
 		},
+		Functions:  funcs.Functions,
+		Validators: validators,
 	}, nil
 }
 
+// siblingVariables returns variables declared by job files
+// in the directory of a var file
+func (p *Store) siblingVariables(path string) []variables.Variable {
+	declared := make([]variables.Variable, 0)
+
+	for _, sibling := range p.Siblings(path, languages.NomadJob) {
+		file, err := p.GetFile(sibling)
+		if err != nil {
+			continue
+		}
+
+		if body, ok := file.File().Body.(*hclsyntax.Body); ok {
+			declared = append(declared, variables.Declared(body)...)
+		}
+	}
+
+	return declared
+}
+
 // Paths implements [decoder.PathReader].
 func (p *Store) Paths(ctx context.Context) []lang.Path {
 	var paths []lang.Path
 
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	for path, val := range p.files {
 		paths = append(paths, lang.Path{
 			Path:       path,
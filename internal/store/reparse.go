@@ -0,0 +1,233 @@
+package store
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// reparse replaces old[start:end] with text and parses again only top-level attributes
+// and blocks touched by the edit, the following ones are copied with shifted positions.
+// ok is false when the whole file has to be parsed, e.g. the edited region has syntax errors
+func reparse(old *hcl.File, start, end int, text []byte, filename string) (file *hcl.File, diags hcl.Diagnostics, ok bool) {
+	oldBody, isSyntax := old.Body.(*hclsyntax.Body)
+	if !isSyntax || start < 0 || start > end || end > len(old.Bytes) {
+		return nil, nil, false
+	}
+
+	src := replaceBytes(old.Bytes, start, end, text)
+
+	deltaByte := len(text) - (end - start)
+	deltaLine := bytes.Count(text, []byte("\n")) - bytes.Count(old.Bytes[start:end], []byte("\n"))
+	endLine := bytes.Count(old.Bytes[:end], []byte("\n")) + 1
+
+	// the region spans from the end of the last item before the edit to the start of
+	// the first item after it, which has to start on a later line to keep its columns
+	var before, after []item
+	regionStart := hcl.InitialPos
+	regionEnd := len(old.Bytes)
+
+	for _, item := range topLevelItems(oldBody) {
+		switch {
+		case item.End.Byte < start:
+			regionStart = item.End
+			before = append(before, item)
+		case item.Start.Byte > end && item.Start.Line > endLine:
+			regionEnd = min(regionEnd, item.Start.Byte)
+			after = append(after, item)
+		}
+	}
+
+	region, diags := hclsyntax.ParseConfig(src[regionStart.Byte:regionEnd+deltaByte], filename, regionStart)
+	if diags.HasErrors() {
+		return nil, nil, false
+	}
+	regionBody := region.Body.(*hclsyntax.Body)
+
+	// items after the edit are copied as the previous file may still be in use
+	shift := shifter{line: deltaLine, byte: deltaByte, copies: make(map[visit]reflect.Value)}
+	for i, item := range after {
+		after[i] = shift.item(item)
+	}
+
+	body := &hclsyntax.Body{
+		Attributes: make(hclsyntax.Attributes),
+		Blocks:     make(hclsyntax.Blocks, 0, len(oldBody.Blocks)),
+	}
+
+	items := append(before, topLevelItems(regionBody)...)
+	items = append(items, after...)
+	for _, item := range items {
+		if item.block != nil {
+			body.Blocks = append(body.Blocks, item.block)
+			continue
+		}
+
+		// redefined attributes are reported by a full parse
+		if _, exists := body.Attributes[item.attr.Name]; exists {
+			return nil, nil, false
+		}
+		body.Attributes[item.attr.Name] = item.attr
+	}
+
+	// the body spans the whole file
+	eof := regionBody.SrcRange.End
+	if len(after) > 0 {
+		eof = oldBody.SrcRange.End
+		eof.Line += deltaLine
+		eof.Byte += deltaByte
+	}
+	body.SrcRange = hcl.Range{Filename: filename, Start: hcl.InitialPos, End: eof}
+	body.EndRange = hcl.Range{Filename: filename, Start: eof, End: eof}
+
+	// a parsed file is needed for its navigation, which points to the body
+	file, _ = hclsyntax.ParseConfig(nil, filename, hcl.InitialPos)
+	*file.Body.(*hclsyntax.Body) = *body
+	file.Bytes = src
+
+	return file, diags, true
+}
+
+func replaceBytes(src []byte, start, end int, text []byte) []byte {
+	out := make([]byte, 0, len(src)-(end-start)+len(text))
+	out = append(out, src[:start]...)
+	out = append(out, text...)
+	out = append(out, src[end:]...)
+
+	return out
+}
+
+// item is a top-level attribute or block of a body
+type item struct {
+	hcl.Range
+
+	attr  *hclsyntax.Attribute
+	block *hclsyntax.Block
+}
+
+// topLevelItems returns attributes and blocks of a body in the order of the source
+func topLevelItems(body *hclsyntax.Body) []item {
+	items := make([]item, 0, len(body.Attributes)+len(body.Blocks))
+	for _, attr := range body.Attributes {
+		items = append(items, item{Range: attr.SrcRange, attr: attr})
+	}
+	for _, block := range body.Blocks {
+		items = append(items, item{Range: block.Range(), block: block})
+	}
+
+	slices.SortFunc(items, func(a, b item) int {
+		return a.Start.Byte - b.Start.Byte
+	})
+
+	return items
+}
+
+var (
+	posType   = reflect.TypeFor[hcl.Pos]()
+	valueType = reflect.TypeFor[cty.Value]()
+)
+
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// shifter deep copies syntax nodes moving all positions by a number of lines and bytes,
+// nodes referenced more than once, e.g. the item of a splat, are copied once
+type shifter struct {
+	line, byte int
+
+	copies map[visit]reflect.Value
+}
+
+func (s *shifter) item(it item) item {
+	it.Range = s.copy(reflect.ValueOf(it.Range)).Interface().(hcl.Range)
+	if it.attr != nil {
+		it.attr = s.copy(reflect.ValueOf(it.attr)).Interface().(*hclsyntax.Attribute)
+	}
+	if it.block != nil {
+		it.block = s.copy(reflect.ValueOf(it.block)).Interface().(*hclsyntax.Block)
+	}
+
+	return it
+}
+
+func (s *shifter) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+
+		key := visit{v.Pointer(), v.Type()}
+		if c, ok := s.copies[key]; ok {
+			return c
+		}
+
+		c := reflect.New(v.Type().Elem())
+		s.copies[key] = c
+		c.Elem().Set(s.copy(v.Elem()))
+
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(s.copy(v.Elem()))
+
+		return c
+	case reflect.Struct:
+		if v.Type() == posType {
+			pos := v.Interface().(hcl.Pos)
+			pos.Line += s.line
+			pos.Byte += s.byte
+
+			return reflect.ValueOf(pos)
+		}
+
+		if v.Type() == valueType {
+			return v
+		}
+
+		// unexported fields are shared, syntax nodes keep no positions in them
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				c.Field(i).Set(s.copy(v.Field(i)))
+			}
+		}
+
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			c.Index(i).Set(s.copy(v.Index(i)))
+		}
+
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(iter.Key(), s.copy(iter.Value()))
+		}
+
+		return c
+	}
+
+	return v
+}
@@ -0,0 +1,185 @@
+package store
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+const reparseSrc = `variable "image" {
+  type = string
+}
+
+locals {
+  tags = [for t in var.tags : upper(t)]
+}
+
+job "app" {
+  group "web" {
+    count = 2
+
+    task "nginx" {
+      config {
+        image = var.image
+        ports = local.ports[*].name
+      }
+    }
+  }
+}
+
+region = "global"
+`
+
+func TestReparse(t *testing.T) {
+	tests := map[string]struct {
+		old  string
+		text string
+	}{
+		"within a block":          {"count = 2", "count = 10"},
+		"adding lines":            {"type = string", "type    = string\n  default = \"nginx\""},
+		"removing lines":          {"locals {\n  tags = [for t in var.tags : upper(t)]\n}\n", ""},
+		"new attribute":           {"\n\njob", "\n\ndatacenters = [\"dc1\"]\n\njob"},
+		"last attribute":          {`"global"`, `"europe"`},
+		"indentation of an item":  {"\nlocals", "\n  locals"},
+		"appending to a file":     {"\"global\"\n", "\"global\"\nnamespace = \"default\"\n"},
+		"splat after the edit":    {`variable "image"`, `variable "img"`},
+		"spanning several blocks": {"string\n}\n\nlocals", "number\n}\n\n\nlocals"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := parse(t, reparseSrc)
+			start := strings.Index(reparseSrc, test.old)
+			end := start + len(test.old)
+
+			file, _, ok := reparse(old, start, end, []byte(test.text), "job.nomad.hcl")
+			if !ok {
+				t.Fatal("expected the edit to be parsed incrementally")
+			}
+
+			expected := parse(t, string(file.Bytes))
+			if !reflect.DeepEqual(file.Body, expected.Body) {
+				t.Errorf("expected the body of a full parse")
+			}
+
+			if !reflect.DeepEqual(old.Body, parse(t, reparseSrc).Body) {
+				t.Errorf("expected the previous file to be left unchanged")
+			}
+		})
+	}
+}
+
+func TestReparseFallsBack(t *testing.T) {
+	tests := map[string]struct {
+		old  string
+		text string
+	}{
+		"syntax error":        {"count = 2", "count = "},
+		"unclosed block":      {"\nregion", "\ngroup \"api\" {\nregion"},
+		"redefined attribute": {"\n\njob", "\n\nregion = \"eu\"\n\njob"},
+		"out of bounds":       {"", ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			start := strings.Index(reparseSrc, test.old)
+			end := start + len(test.old)
+			if test.old == "" {
+				start, end = 0, len(reparseSrc)+1
+			}
+
+			if _, _, ok := reparse(parse(t, reparseSrc), start, end, []byte(test.text), "job.nomad.hcl"); ok {
+				t.Errorf("expected the whole file to be parsed")
+			}
+		})
+	}
+}
+
+// replace returns an edit replacing the first occurrence of old with text
+func replace(old, text string) Edit {
+	return func(src []byte) (int, int, []byte) {
+		start := strings.Index(string(src), old)
+		return start, start + len(old), []byte(text)
+	}
+}
+
+func TestEditHCL(t *testing.T) {
+	doc := NewDocument("nomad")
+	doc.ParseHCL([]byte(reparseSrc), "job.nomad.hcl")
+
+	_, diags, _ := doc.EditHCL(2, []Edit{replace("count = 2", "count = ")}, "job.nomad.hcl")
+	if !diags.HasErrors() {
+		t.Fatalf("expected syntax errors of a full parse, recieved: %v", diags)
+	}
+
+	// the document has errors now, so the fix is parsed as a whole
+	_, diags, _ = doc.EditHCL(3, []Edit{replace("count = ", "count = 3")}, "job.nomad.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("expected no errors, recieved: %v", diags)
+	}
+
+	if !strings.Contains(string(doc.File().Bytes), "count = 3") {
+		t.Errorf("expected the edit to be applied, recieved: %s", doc.File().Bytes)
+	}
+}
+
+func TestEditHCLInOrder(t *testing.T) {
+	doc := NewDocument("nomad")
+	doc.ParseHCL([]byte(reparseSrc), "job.nomad.hcl")
+
+	// the second edit applies to the content after the first one
+	_, _, ok := doc.EditHCL(2, []Edit{replace("count = 2", "count = 10"), replace("count = 10", "count = 11")}, "job.nomad.hcl")
+	if !ok || !strings.Contains(string(doc.File().Bytes), "count = 11") {
+		t.Fatalf("expected both edits to be applied, recieved: %s", doc.File().Bytes)
+	}
+
+	if _, _, ok := doc.EditHCL(1, []Edit{replace("count = 11", "count = 1")}, "job.nomad.hcl"); ok {
+		t.Errorf("expected edits of an older version to be dropped")
+	}
+
+	if !strings.Contains(string(doc.File().Bytes), "count = 11") || doc.Version() != 2 {
+		t.Errorf("expected the document to be left unchanged, recieved version %d: %s", doc.Version(), doc.File().Bytes)
+	}
+}
+
+func parse(t *testing.T, src string) *hcl.File {
+	file, diags := hclsyntax.ParseConfig([]byte(src), "job.nomad.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	return file
+}
+
+func TestReparseRandomEdits(t *testing.T) {
+	snippets := []string{
+		"", "\n", "\n\n", " ", "x", "1", `"a"`, "{", "}", "#", "# note\n", "[", "]",
+		"count = 3\n", "\nregion = \"eu\"\n", "locals {\n  a = 1\n}\n", "${", "var.image", "\"",
+		"group \"api\" {\n}\n", "[for t in var.tags : t]", "a = <<EOT\nb\nEOT\n",
+	}
+
+	rnd := rand.New(rand.NewPCG(1, 2))
+	for i := range 5000 {
+		start := rnd.IntN(len(reparseSrc) + 1)
+		end := start + rnd.IntN(min(len(reparseSrc)-start, 40)+1)
+		text := snippets[rnd.IntN(len(snippets))]
+
+		file, _, ok := reparse(parse(t, reparseSrc), start, end, []byte(text), "job.nomad.hcl")
+		if !ok {
+			continue
+		}
+
+		expected, diags := hclsyntax.ParseConfig(file.Bytes, "job.nomad.hcl", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("edit %d: expected errors of %q at %d-%d to fall back to a full parse", i, text, start, end)
+		}
+
+		if !reflect.DeepEqual(file.Body, expected.Body) {
+			t.Fatalf("edit %d: expected the body of a full parse after %q at %d-%d", i, text, start, end)
+		}
+	}
+}
@@ -1,18 +1,54 @@
 package store
 
-import "errors"
+import (
+	"errors"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/hashicorp/hcl-lang/reference"
+
+	"github.com/loczek/nomad-ls/internal/languages"
+)
 
 type Store struct {
 	files map[string]*Document
+	// nodeTargets are attributes and meta of nodes loaded from a node status,
+	// referenced by all job files
+	nodeTargets *reference.Targets
+
+	mu *sync.RWMutex
 }
 
 func NewStore() Store {
 	return Store{
-		files: make(map[string]*Document),
+		files:       make(map[string]*Document),
+		nodeTargets: &reference.Targets{},
+		mu:          &sync.RWMutex{},
 	}
 }
 
+// SetNodeTargets replaces targets of attributes and meta of nodes
+func (s *Store) SetNodeTargets(targets reference.Targets) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	*s.nodeTargets = targets
+}
+
+// NodeTargets returns targets of attributes and meta of nodes
+func (s *Store) NodeTargets() reference.Targets {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return *s.nodeTargets
+}
+
 func (s *Store) GetFile(path string) (*Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	if file, ok := s.files[path]; ok {
 		return file, nil
 	}
@@ -21,15 +57,38 @@ func (s *Store) GetFile(path string) (*Document, error) {
 }
 
 func (s *Store) AddFile(path string, content *Document) *Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.files[path] = content
 	return s.files[path]
 }
 
+// AddFileIfNotOpen adds the document unless a document opened in the editor
+// is stored at path already, the check and the insert are atomic
+func (s *Store) AddFileIfNotOpen(path string, content *Document) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if file, ok := s.files[path]; ok && file.IsOpen() {
+		return false
+	}
+
+	s.files[path] = content
+	return true
+}
+
 func (s *Store) RemoveFile(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	delete(s.files, path)
 }
 
 func (s *Store) Contains(path string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	if _, ok := s.files[path]; ok {
 		return true
 	}
@@ -37,6 +96,32 @@ func (s *Store) Contains(path string) bool {
 	return false
 }
 
+// Files returns a snapshot of all documents in the store
 func (s *Store) Files() map[string]*Document {
-	return s.files
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	files := make(map[string]*Document, len(s.files))
+	for path, file := range s.files {
+		files[path] = file
+	}
+
+	return files
+}
+
+// Siblings returns paths of documents of the language in the directory of path, sorted
+func (s *Store) Siblings(path string, lang languages.LanguageID) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dir := filepath.Dir(path)
+
+	paths := make([]string, 0)
+	for _, p := range slices.Sorted(maps.Keys(s.files)) {
+		if p != path && filepath.Dir(p) == dir && s.files[p].Language == lang {
+			paths = append(paths, p)
+		}
+	}
+
+	return paths
 }
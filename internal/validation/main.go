@@ -10,6 +10,8 @@ import (
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl/v2"
+
+	"github.com/loczek/nomad-ls/internal/references"
 )
 
 func UnreferencedOrigins(ctx context.Context, pathCtx *decoder.PathContext) lang.DiagnosticsMap {
@@ -24,8 +26,24 @@ func UnreferencedOrigins(ctx context.Context, pathCtx *decoder.PathContext) lang
 
 		address := localOrigin.Address()
 
-		supported := []string{"var", "local"}
 		firstStep := address[0].String()
+
+		// blocks such as `port "http"` are targets of labels only,
+		// their addresses are not variables of expressions
+		if references.IsLabelRoot(firstStep) && !references.IsLabelOrigin(localOrigin) {
+			fileName := origin.OriginRange().Filename
+			d := &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unknown variable",
+				Detail:   fmt.Sprintf("There is no variable named %q, `%s` blocks are referred to by their labels.", firstStep, firstStep),
+				Subject:  origin.OriginRange().Ptr(),
+			}
+			diagsMap[fileName] = diagsMap[fileName].Append(d)
+
+			continue
+		}
+
+		supported := []string{"var", "local"}
 		if !slices.Contains(supported, firstStep) {
 			continue
 		}
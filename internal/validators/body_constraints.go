@@ -0,0 +1,46 @@
+package custom_validators
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	schemautils "github.com/loczek/nomad-ls/internal/schemaUtils"
+)
+
+var _ validator.Validator = (*BodyConstraints)(nil)
+
+// BodyConstraints enforces constraints of block bodies. Schemas passed to validators
+// are copies made by hcl-lang when merging dependent bodies, so the body schemas
+// blocks are declared with are followed from Schema down to each block instead
+type BodyConstraints struct {
+	Schema      *schema.BodySchema
+	Constraints schemautils.Constraints
+}
+
+// declaredBodiesKey holds body schemas of the block enclosing the visited node
+type declaredBodiesKey struct{}
+
+func (v BodyConstraints) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	block, ok := node.(*hclsyntax.Block)
+	if !ok {
+		return ctx, diags
+	}
+
+	parents, ok := ctx.Value(declaredBodiesKey{}).([]*schema.BodySchema)
+	if !ok {
+		parents = []*schema.BodySchema{v.Schema}
+	}
+
+	bodies := schemautils.DeclaredBodies(block, parents)
+	for _, constraint := range v.Constraints.Of(bodies) {
+		diags = append(diags, constraint.Validate(block.Body)...)
+	}
+
+	return context.WithValue(ctx, declaredBodiesKey{}, bodies), diags
+}
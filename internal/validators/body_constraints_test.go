@@ -0,0 +1,78 @@
+package custom_validators_test
+
+import "testing"
+
+func TestBodyConstraints(t *testing.T) {
+	summaries := validate(t, "testdata/body_constraints.nomad.hcl")
+
+	expected := map[string]int{
+		`Required attribute "change_signal" not specified`:   2,
+		`Required attribute "access_mode" not specified`:     1,
+		`Required attribute "attachment_mode" not specified`: 1,
+		`Only one of "source", "data" can be specified`:      1,
+		`One of "source", "data" must be specified`:          1,
+	}
+
+	expectCounts(t, summaries, expected)
+}
+
+func TestDurationsAndSizes(t *testing.T) {
+	summaries := validate(t, "testdata/durations.nomad.hcl")
+
+	expected := map[string]int{
+		"Invalid duration":   1,
+		"Duration too short": 1,
+		"Invalid size":       1,
+		`"min_healthy_time" must be less than "healthy_deadline"`: 1,
+		`"timeout" must be less than "interval"`:                  1,
+	}
+
+	expectCounts(t, summaries, expected)
+}
+
+// expectCounts checks how many times each summary was reported
+func expectCounts(t *testing.T, summaries []string, expected map[string]int) {
+	t.Helper()
+
+	for summary, count := range expected {
+		recieved := 0
+		for _, s := range summaries {
+			if s == summary {
+				recieved++
+			}
+		}
+
+		if recieved != count {
+			t.Errorf("expected %d of %q, recieved: %d", count, summary, recieved)
+		}
+	}
+}
+
+func TestCrons(t *testing.T) {
+	summaries := validate(t, "testdata/crons.nomad.hcl")
+
+	expected := map[string]int{
+		"Invalid cron expression":     3,
+		"Cron expression never fires": 1,
+		"Invalid time zone":           1,
+	}
+
+	expectCounts(t, summaries, expected)
+}
+
+func TestOperators(t *testing.T) {
+	summaries := validate(t, "testdata/operators.nomad.hcl")
+
+	expected := map[string]int{
+		`Unknown operator "~="`:                          1,
+		`Unknown operator "distinct_hosts"`:              1,
+		`Invalid value for "regexp" operator`:            1,
+		`Invalid value for "semver" operator`:            1,
+		`Invalid value for "distinct_property" operator`: 1,
+		"Unexpected value":                               1,
+		`Required attribute "value" not specified`:       1,
+		`Required attribute "attribute" not specified`:   1,
+	}
+
+	expectCounts(t, summaries, expected)
+}
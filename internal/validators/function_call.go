@@ -0,0 +1,72 @@
+package custom_validators
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+)
+
+var _ validator.Validator = (*FunctionCall)(nil)
+
+// FunctionCall evaluates calls of functions with constant arguments
+// and reports errors, e.g. arguments of wrong types. Impure functions such as
+// `file` or `bcrypt` only check their arguments so that diagnostics do not
+// depend on files or randomness
+type FunctionCall struct{}
+
+func (v FunctionCall) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	attr, ok := node.(*hclsyntax.Attribute)
+	if !ok {
+		return ctx, diags
+	}
+
+	evalCtx := eval.Context(filepath.Dir(attr.SrcRange.Filename), nil)
+
+	for _, call := range constantCalls(attr.Expr) {
+		_, callDiags := call.Value(evalCtx)
+		for _, diag := range callDiags {
+			if diag.Severity == hcl.DiagError {
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return ctx, diags
+}
+
+// constantCalls returns outermost function calls without references,
+// nested calls are evaluated as arguments of the outer call
+func constantCalls(expr hclsyntax.Expression) []*hclsyntax.FunctionCallExpr {
+	calls := make([]*hclsyntax.FunctionCallExpr, 0)
+
+	hclsyntax.VisitAll(expr, func(node hclsyntax.Node) hcl.Diagnostics {
+		call, ok := node.(*hclsyntax.FunctionCallExpr)
+		if !ok || len(call.Variables()) > 0 {
+			return nil
+		}
+
+		for _, outer := range calls {
+			if rangeContains(outer.Range(), call.Range()) {
+				return nil
+			}
+		}
+
+		calls = append(calls, call)
+
+		return nil
+	})
+
+	return calls
+}
+
+func rangeContains(outer hcl.Range, inner hcl.Range) bool {
+	return outer.Start.Byte <= inner.Start.Byte && inner.End.Byte <= outer.End.Byte
+}
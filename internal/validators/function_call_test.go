@@ -0,0 +1,12 @@
+package custom_validators_test
+
+import (
+	"testing"
+)
+
+func TestFunctionCall(t *testing.T) {
+	expectCounts(t, validate(t, "testdata/function_calls.nomad.hcl"), map[string]int{
+		"Invalid function argument": 4,
+		"Error in function call":    1,
+	})
+}
@@ -0,0 +1,240 @@
+package custom_validators
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+)
+
+var _ validator.Validator = (*GroupLifecycle)(nil)
+
+var lifecycleHooks = []string{"prestart", "poststart", "poststop"}
+
+// taskReferences maps blocks to their attributes naming a task of the group. The jobspec
+// has no `depends_on`, dependencies between tasks are lifecycle hooks and these references,
+// `volume_mount` blocks depending on group volumes are checked by [VolumeMount]
+var taskReferences = map[string]string{
+	"service": "task",
+	"check":   "task",
+}
+
+// destinationBlocks are blocks of a task writing files to a `destination`
+// relative to the task directory
+var destinationBlocks = []string{"template", "artifact"}
+
+// allocDir stands in for the allocation directory when checking destinations
+const allocDir = "/alloc-dir/alloc-id"
+
+// GroupLifecycle enforces the rules of Nomad's job validation for tasks of a group,
+// i.e. leaders, lifecycle hooks, destinations of files and references to tasks which have to exist
+type GroupLifecycle struct{}
+
+func (v GroupLifecycle) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	group, ok := node.(*hclsyntax.Block)
+	if !ok || group.Type != "group" || nodeSchema == nil {
+		return ctx, diags
+	}
+
+	tasks := make([]*hclsyntax.Block, 0)
+	hasDynamicTasks := false
+	for _, block := range group.Body.Blocks {
+		if isDynamicBlock(block, "task") {
+			hasDynamicTasks = true
+		}
+
+		if block.Type == "task" && len(block.Labels) > 0 {
+			tasks = append(tasks, block)
+		}
+	}
+
+	diags = append(diags, leaderDiags(group, tasks)...)
+
+	for _, task := range tasks {
+		diags = append(diags, lifecycleDiags(task)...)
+		diags = append(diags, destinationDiags(task)...)
+	}
+
+	if !hasDynamicTasks {
+		diags = append(diags, taskReferenceDiags(group, group.Body, tasks)...)
+	}
+
+	return ctx, diags
+}
+
+func leaderDiags(group *hclsyntax.Block, tasks []*hclsyntax.Block) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	leaders := make([]*hclsyntax.Attribute, 0)
+	names := make([]string, 0)
+	for _, task := range tasks {
+		attr, ok := task.Body.Attributes["leader"]
+		if !ok {
+			continue
+		}
+
+		if val, ok := eval.StaticValue(attr.Expr, cty.Bool); ok && val.True() {
+			leaders = append(leaders, attr)
+			names = append(names, fmt.Sprintf("%q", task.Labels[0]))
+		}
+	}
+
+	if len(leaders) < 2 {
+		return diags
+	}
+
+	for _, attr := range leaders {
+		rng := attr.SrcRange
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Only one task may be marked as leader",
+			Detail:   fmt.Sprintf("Group %q has multiple leader tasks: %s.", groupName(group), strings.Join(names, ", ")),
+			Subject:  &rng,
+		})
+	}
+
+	return diags
+}
+
+func lifecycleDiags(task *hclsyntax.Block) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, lifecycle := range task.Body.Blocks {
+		if lifecycle.Type != "lifecycle" {
+			continue
+		}
+
+		hookAttr, ok := lifecycle.Body.Attributes["hook"]
+		if !ok {
+			continue
+		}
+
+		hookVal, ok := eval.StaticValue(hookAttr.Expr, cty.String)
+		if !ok {
+			continue
+		}
+
+		hook := hookVal.AsString()
+		if !slices.Contains(lifecycleHooks, hook) {
+			rng := hookAttr.Expr.Range()
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Invalid lifecycle hook %q", hook),
+				Detail:   fmt.Sprintf("Valid hooks are %q, %q and %q.", lifecycleHooks[0], lifecycleHooks[1], lifecycleHooks[2]),
+				Subject:  &rng,
+			})
+			continue
+		}
+
+		sidecarAttr, ok := lifecycle.Body.Attributes["sidecar"]
+		if !ok || hook != "poststop" {
+			continue
+		}
+
+		if sidecar, ok := eval.StaticValue(sidecarAttr.Expr, cty.Bool); ok && sidecar.True() {
+			rng := sidecarAttr.SrcRange
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Poststop task can not be a sidecar",
+				Detail:   fmt.Sprintf("Task %q runs after the main tasks have stopped, so it can not be long-lived.", task.Labels[0]),
+				Subject:  &rng,
+			})
+		}
+	}
+
+	return diags
+}
+
+// destinationDiags reports templates and artifacts writing outside of the allocation directory,
+// which Nomad rejects when the job is submitted
+func destinationDiags(task *hclsyntax.Block) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, block := range task.Body.Blocks {
+		if !slices.Contains(destinationBlocks, block.Type) {
+			continue
+		}
+
+		attr, ok := block.Body.Attributes["destination"]
+		if !ok {
+			continue
+		}
+
+		val, ok := eval.StaticValue(attr.Expr, cty.String)
+		if !ok || !escapesAllocDir(val.AsString()) {
+			continue
+		}
+
+		rng := attr.Expr.Range()
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Destination escapes the allocation directory",
+			Detail:   fmt.Sprintf("Destination %q of %s in task %q points outside of the allocation directory.", val.AsString(), block.Type, task.Labels[0]),
+			Subject:  &rng,
+		})
+	}
+
+	return diags
+}
+
+// escapesAllocDir reports whether a destination relative to the task directory
+// points outside of the allocation directory, absolute paths are relative to it too
+func escapesAllocDir(dest string) bool {
+	joined := path.Join(allocDir, "task", dest)
+
+	return joined != allocDir && !strings.HasPrefix(joined, allocDir+"/")
+}
+
+// taskReferenceDiags reports references to tasks which are not declared in the group,
+// e.g. `task` of a group service
+func taskReferenceDiags(group *hclsyntax.Block, body *hclsyntax.Body, tasks []*hclsyntax.Block) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, block := range body.Blocks {
+		diags = append(diags, taskReferenceDiags(group, block.Body, tasks)...)
+
+		attrName, ok := taskReferences[block.Type]
+		if !ok {
+			continue
+		}
+
+		attr, ok := block.Body.Attributes[attrName]
+		if !ok {
+			continue
+		}
+
+		val, ok := eval.StaticValue(attr.Expr, cty.String)
+		if !ok || val.AsString() == "" {
+			continue
+		}
+
+		name := val.AsString()
+		declared := slices.ContainsFunc(tasks, func(task *hclsyntax.Block) bool {
+			return task.Labels[0] == name
+		})
+		if declared {
+			continue
+		}
+
+		rng := attr.Expr.Range()
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Unknown task %q", name),
+			Detail:   fmt.Sprintf("Task %q is not declared in group %q.", name, groupName(group)),
+			Subject:  &rng,
+		})
+	}
+
+	return diags
+}
@@ -0,0 +1,31 @@
+package custom_validators_test
+
+import "testing"
+
+func TestGroupLifecycle(t *testing.T) {
+	summaries := validate(t, "testdata/group_lifecycle.nomad.hcl")
+
+	expected := map[string]int{
+		"Only one task may be marked as leader":        2,
+		`Invalid lifecycle hook "prestop"`:             1,
+		"Poststop task can not be a sidecar":           1,
+		`Unknown task "exporter"`:                      1,
+		`Unknown task "sidecar"`:                       1,
+		`Unknown task "server"`:                        0,
+		"Destination escapes the allocation directory": 1,
+	}
+
+	for summary, count := range expected {
+		recieved := 0
+		for _, s := range summaries {
+			if s == summary {
+				recieved++
+			}
+		}
+
+		if recieved != count {
+			t.Errorf("expected %d of %q, recieved: %d", count, summary, recieved)
+		}
+	}
+
+}
@@ -0,0 +1,112 @@
+package custom_validators
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+	"github.com/loczek/nomad-ls/internal/references"
+	"github.com/loczek/nomad-ls/internal/scope"
+)
+
+var _ validator.Validator = (*PortLabel)(nil)
+
+type portLabelsCtxKey struct{}
+
+type parentBlockCtxKey struct{}
+
+// portLabels are labels of ports declared within the enclosing group
+type portLabels struct {
+	group  string
+	labels []string
+}
+
+// PortLabel reports port labels which are not declared
+// in a `network` block of the enclosing group
+type PortLabel struct{}
+
+func (v PortLabel) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	switch n := node.(type) {
+	case *hclsyntax.Block:
+		if n.Type == "group" {
+			labels, ok := references.PortLabels(n.Body)
+			if !ok {
+				// ports declared by dynamic blocks can not be resolved
+				ctx = context.WithValue(ctx, portLabelsCtxKey{}, (*portLabels)(nil))
+			} else {
+				ctx = context.WithValue(ctx, portLabelsCtxKey{}, &portLabels{
+					group:  strings.Join(n.Labels, "."),
+					labels: labels,
+				})
+			}
+		}
+
+		return context.WithValue(ctx, parentBlockCtxKey{}, n.Type), diags
+	case *hclsyntax.Attribute:
+		if nodeSchema == nil {
+			return ctx, diags
+		}
+
+		parent, _ := ctx.Value(parentBlockCtxKey{}).(string)
+		if ref, ok := references.LabelReferenceOf(parent, n.Name); !ok || ref.ScopeId != scope.PortScope {
+			return ctx, diags
+		}
+
+		ports, _ := ctx.Value(portLabelsCtxKey{}).(*portLabels)
+		if ports == nil {
+			return ctx, diags
+		}
+
+		for _, expr := range references.ElementExprs(n.Expr) {
+			val, ok := eval.StaticValue(expr, cty.String)
+			if !ok {
+				continue
+			}
+
+			label := val.AsString()
+
+			// numeric ports are allowed with `address_mode = "driver"`
+			if _, err := strconv.Atoi(label); err == nil {
+				continue
+			}
+
+			if slices.Contains(ports.labels, label) {
+				continue
+			}
+
+			rng := expr.Range()
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Unknown port label %q", label),
+				Detail:   unknownPortLabelDetail(label, ports),
+				Subject:  &rng,
+			})
+		}
+	}
+
+	return ctx, diags
+}
+
+func unknownPortLabelDetail(label string, ports *portLabels) string {
+	if len(ports.labels) == 0 {
+		return fmt.Sprintf("Group %q does not declare any ports, %q has to be declared as `port %q {}` in its `network` block.", ports.group, label, label)
+	}
+
+	quoted := make([]string, 0, len(ports.labels))
+	for _, l := range ports.labels {
+		quoted = append(quoted, strconv.Quote(l))
+	}
+
+	return fmt.Sprintf("Port %q is not declared in the `network` block of group %q. Available port labels: %s.", label, ports.group, strings.Join(quoted, ", "))
+}
@@ -0,0 +1,69 @@
+package custom_validators_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/store"
+)
+
+func validate(t *testing.T, path string) []string {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := store.NewStore()
+	doc := store.NewDocument(languages.NomadJob)
+	doc.ParseHCL(src, path)
+	s.AddFile(path, doc)
+
+	pathDec, err := decoder.NewDecoder(&s).Path(lang.Path{
+		Path:       path,
+		LanguageID: languages.NomadJob.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags, err := pathDec.ValidateFile(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summaries := make([]string, 0, len(diags))
+	for _, diag := range diags {
+		summaries = append(summaries, diag.Summary)
+	}
+
+	return summaries
+}
+
+func TestPortLabel(t *testing.T) {
+	summaries := validate(t, "testdata/port_labels.nomad.hcl")
+
+	expected := map[string]bool{
+		`Unknown port label "htp"`:     false,
+		`Unknown port label "metrics"`: false,
+	}
+
+	for _, summary := range summaries {
+		if _, ok := expected[summary]; ok {
+			expected[summary] = true
+		} else if strings.HasPrefix(summary, "Unknown port label") {
+			t.Errorf("unexpected diagnostic: %s", summary)
+		}
+	}
+
+	for summary, found := range expected {
+		if !found {
+			t.Errorf("expected diagnostic: %s", summary)
+		}
+	}
+}
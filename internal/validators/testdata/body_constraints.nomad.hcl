@@ -0,0 +1,34 @@
+job "shop" {
+  group "api" {
+    volume "data" {
+      type   = "csi"
+      source = "data"
+    }
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = "shop"
+      }
+
+      identity {
+        name        = "vault"
+        change_mode = "signal"
+      }
+
+      template {
+        destination = "local/config.yml"
+        change_mode = "signal"
+        source      = "config.yml.tpl"
+        data        = "key: value"
+      }
+
+      template {
+        destination   = "local/env"
+        change_mode   = "signal"
+        change_signal = "SIGHUP"
+      }
+    }
+  }
+}
@@ -0,0 +1,26 @@
+job "reports" {
+  type = "batch"
+
+  periodic {
+    crons     = ["0 */2 * * *", "0 25 * * *", "0 0 30 2 *"]
+    time_zone = "Mars/Olympus_Mons"
+  }
+
+  group "reports" {
+    task "generate" {
+      driver = "exec"
+
+      config {
+        command = "generate"
+      }
+
+      schedule {
+        cron {
+          start    = "0 9,12 * * mon-fri"
+          end      = "30"
+          timezone = "America/New_York"
+        }
+      }
+    }
+  }
+}
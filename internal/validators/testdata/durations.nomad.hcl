@@ -0,0 +1,53 @@
+job "shop" {
+  group "api" {
+    shutdown_delay = "10 seconds"
+
+    update {
+      min_healthy_time = "10m"
+      healthy_deadline = "5m"
+    }
+
+    network {
+      port "http" {}
+    }
+
+    service {
+      name = "api"
+      port = "http"
+
+      check {
+        type     = "http"
+        path     = "/health"
+        interval = "10s"
+        timeout  = "30s"
+      }
+
+      check {
+        type     = "tcp"
+        interval = "500ms"
+        timeout  = "${var.timeout}"
+      }
+    }
+
+    task "server" {
+      driver       = "docker"
+      kill_timeout = "1m30s"
+
+      config {
+        image             = "shop"
+        shm_size          = "64m"
+        memory_hard_limit = "2048"
+      }
+
+      # sizes of the docker driver do not apply to other config blocks
+      secret {
+        provider = "nomad"
+        path     = "shop"
+
+        config {
+          shm_size = "64 megabytes"
+        }
+      }
+    }
+  }
+}
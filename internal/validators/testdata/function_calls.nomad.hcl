@@ -0,0 +1,39 @@
+locals {
+  name    = upper("web")
+  subnet  = cidrsubnet("10.0.0.0/16", 8, 2)
+  encoded = jsonencode({ port = 8080 })
+  size    = length("nginx")
+  second  = index(["a", "b"], 1)
+
+  # arguments of wrong types
+  bad_abs    = abs("ten")
+  bad_join   = join(",", "a")
+  bad_nested = upper(abs("ten"))
+
+  # impure functions only check their arguments
+  hashed     = bcrypt("secret")
+  config     = file("missing.txt")
+  id         = uuidv4()
+  bad_exists = fileexists(["a"])
+
+  # references are not evaluated
+  image = format("%s:%s", var.image, "1")
+}
+
+variable "image" {
+  type    = string
+  default = "nginx"
+}
+
+job "app" {
+  group "app" {
+    task "app" {
+      driver = "docker"
+
+      config {
+        image = local.image
+        args  = [try(jsondecode("{"), "fallback"), cidrhost("10.0.0.0/33", 1)]
+      }
+    }
+  }
+}
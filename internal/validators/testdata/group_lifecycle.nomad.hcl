@@ -0,0 +1,79 @@
+job "shop" {
+  group "api" {
+    service {
+      name = "api"
+      task = "server"
+
+      check {
+        type     = "script"
+        command  = "/bin/true"
+        task     = "sidecar"
+        interval = "10s"
+        timeout  = "2s"
+      }
+    }
+
+    service {
+      name = "metrics"
+      task = "exporter"
+    }
+
+    task "server" {
+      driver = "docker"
+      leader = true
+
+      config {
+        image = "shop"
+      }
+
+      template {
+        data        = "port = 8080"
+        destination = "local/../../alloc/config.toml"
+      }
+
+      template {
+        data        = "secret"
+        destination = "/secrets/token"
+      }
+
+      artifact {
+        source      = "https://example.com/assets.tar.gz"
+        destination = "../../../assets"
+      }
+    }
+
+    task "proxy" {
+      driver = "docker"
+      leader = true
+
+      config {
+        image = "envoy"
+      }
+    }
+
+    task "migrate" {
+      driver = "docker"
+
+      lifecycle {
+        hook = "prestop"
+      }
+
+      config {
+        image = "shop"
+      }
+    }
+
+    task "cleanup" {
+      driver = "docker"
+
+      lifecycle {
+        hook    = "poststop"
+        sidecar = true
+      }
+
+      config {
+        image = "shop"
+      }
+    }
+  }
+}
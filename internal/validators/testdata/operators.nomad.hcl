@@ -0,0 +1,58 @@
+job "shop" {
+  constraint {
+    attribute = "${attr.kernel.name}"
+    operator  = "~="
+    value     = "linux"
+  }
+
+  constraint {
+    attribute = "${attr.kernel.version}"
+    regexp    = "linux|(darwin"
+  }
+
+  constraint {
+    attribute = "${attr.vault.version}"
+    operator  = "semver"
+    value     = "~> 1.0"
+  }
+
+  constraint {
+    attribute = "${meta.rack}"
+    operator  = "is_set"
+    value     = "true"
+  }
+
+  constraint {
+    operator = "distinct_hosts"
+    value    = "true"
+  }
+
+  constraint {
+    distinct_property = "${meta.rack}"
+    value             = "0"
+  }
+
+  constraint {
+    attribute = "${node.class}"
+    operator  = "!="
+  }
+
+  constraint {
+    value = "linux"
+  }
+
+  group "api" {
+    affinity {
+      attribute = "${node.datacenter}"
+      operator  = "distinct_hosts"
+      value     = "dc1"
+    }
+
+    affinity {
+      attribute = "${attr.driver.docker.version}"
+      operator  = "version"
+      value     = ">= 20.10"
+      weight    = 50
+    }
+  }
+}
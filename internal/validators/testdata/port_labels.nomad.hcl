@@ -0,0 +1,32 @@
+job "shop" {
+  group "api" {
+    network {
+      port "http" {}
+      port "grpc" {
+        to = 9090
+      }
+    }
+
+    service {
+      port = "htp"
+
+      check {
+        type = "http"
+        port = "grpc"
+      }
+    }
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = "shop"
+        ports = ["http", "metrics"]
+      }
+
+      service {
+        port = "8080"
+      }
+    }
+  }
+}
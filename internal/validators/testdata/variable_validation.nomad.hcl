@@ -0,0 +1,53 @@
+variable "count" {
+  type    = number
+  default = 0
+
+  validation {
+    condition     = var.count > 0
+    error_message = "Count must be positive."
+  }
+}
+
+variable "region" {
+  type    = string
+  default = "global"
+
+  validation {
+    condition     = var.region != ""
+    error_message = "Region must not be empty."
+  }
+}
+
+variable "image" {
+  type    = string
+  default = "nginx"
+
+  validation {
+    condition     = length(var.image) > 10
+    error_message = "Image must be longer than 10 characters."
+  }
+}
+
+# conditions referencing other variables can not be decided
+variable "tag" {
+  type    = string
+  default = "latest"
+
+  validation {
+    condition     = var.tag != var.image
+    error_message = "Tag must differ from the image."
+  }
+}
+
+variable "ports" {
+  type    = list(number)
+  default = ["http"]
+}
+
+job "app" {
+  region = var.region
+
+  group "app" {
+    count = var.count
+  }
+}
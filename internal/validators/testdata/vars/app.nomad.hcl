@@ -0,0 +1,31 @@
+variable "image" {
+  type        = string
+  description = "Docker image of the app"
+}
+
+variable "count" {
+  type    = number
+  default = 1
+
+  validation {
+    condition     = var.count < 10
+    error_message = "Count must be less than 10."
+  }
+}
+
+variable "ports" {
+  type    = list(number)
+  default = []
+}
+
+variables {
+  region = "global"
+}
+
+job "app" {
+  region = var.region
+
+  group "app" {
+    count = var.count
+  }
+}
@@ -0,0 +1,2 @@
+count = "three"
+image = var.default_image
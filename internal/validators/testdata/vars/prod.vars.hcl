@@ -0,0 +1,11 @@
+image  = "nginx:1.27"
+region = "eu"
+
+# strings of digits are converted to numbers
+count = "3"
+
+# invalid values
+ports = [80, "http"]
+
+# unknown variables
+replicas = 3
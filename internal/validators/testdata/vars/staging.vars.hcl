@@ -0,0 +1 @@
+count = 12
@@ -0,0 +1,31 @@
+job "shop" {
+  group "db" {
+    volume "data" {
+      type   = "host"
+      source = "postgres"
+    }
+
+    volume "backups" {
+      type   = "host"
+      source = "backups"
+    }
+
+    task "postgres" {
+      driver = "docker"
+
+      config {
+        image = "postgres"
+      }
+
+      volume_mount {
+        volume      = "data"
+        destination = "/var/lib/postgresql/data"
+      }
+
+      volume_mount {
+        volume      = "dta"
+        destination = "/tmp"
+      }
+    }
+  }
+}
@@ -0,0 +1,49 @@
+package custom_validators
+
+import (
+	"context"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+	"github.com/loczek/nomad-ls/internal/variables"
+)
+
+var _ validator.Validator = (*VariableValidation)(nil)
+
+// VariableValidation reports defaults of `variable` blocks which
+// do not conform to the type or fail conditions of `validation` blocks.
+// Nomad only validates the final value, which var files and `-var` may override,
+// so these are warnings
+type VariableValidation struct{}
+
+func (v VariableValidation) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	block, ok := node.(*hclsyntax.Block)
+	if !ok {
+		return ctx, diags
+	}
+
+	if _, ok := nodeSchema.(*schema.BlockSchema); !ok {
+		return ctx, diags
+	}
+
+	variable, ok := variables.FromBlock(block)
+	if !ok || variable.Default == nil {
+		return ctx, diags
+	}
+
+	val, diags := variable.DefaultValue(eval.Context)
+
+	for _, diag := range variable.Validate(eval.Context, val, variable.Default.Range()) {
+		diag.Severity = hcl.DiagWarning
+		diag.Summary = "Default value fails validation"
+		diags = append(diags, diag)
+	}
+
+	return ctx, diags
+}
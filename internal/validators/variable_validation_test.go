@@ -0,0 +1,13 @@
+package custom_validators_test
+
+import (
+	"testing"
+)
+
+func TestVariableValidation(t *testing.T) {
+	expectCounts(t, validate(t, "testdata/variable_validation.nomad.hcl"), map[string]int{
+		"Default value fails validation":     2,
+		"Invalid value for variable":         0,
+		"Invalid default value for variable": 1,
+	})
+}
@@ -0,0 +1,67 @@
+package custom_validators
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+	"github.com/loczek/nomad-ls/internal/variables"
+)
+
+var _ validator.Validator = (*VariableValue)(nil)
+
+// VariableValue reports values of var files which are not constant,
+// can not be converted to the type of the variable or fail its validation
+type VariableValue struct {
+	Variables []variables.Variable
+}
+
+func (v VariableValue) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	attr, ok := node.(*hclsyntax.Attribute)
+	if !ok {
+		return ctx, diags
+	}
+
+	attrSchema, ok := nodeSchema.(*schema.AttributeSchema)
+	if !ok {
+		return ctx, diags
+	}
+
+	// var files are evaluated without variables and functions
+	val, valDiags := attr.Expr.Value(nil)
+	if valDiags.HasErrors() {
+		return ctx, valDiags
+	}
+
+	c, ok := attrSchema.Constraint.(schema.LiteralType)
+	if !ok {
+		return ctx, diags
+	}
+
+	val, err := convert.Convert(val, c.Type)
+	if err != nil {
+		return ctx, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid value for variable",
+			Detail:   fmt.Sprintf("The value of %q is not compatible with its type %s: %s", attr.Name, typeexpr.TypeString(c.Type), err),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+
+	for _, variable := range v.Variables {
+		if variable.Name == attr.Name {
+			return ctx, append(diags, variable.Validate(eval.Context, val, attr.Expr.Range())...)
+		}
+	}
+
+	return ctx, diags
+}
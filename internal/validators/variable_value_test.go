@@ -0,0 +1,67 @@
+package custom_validators_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/store"
+)
+
+func validateVars(t *testing.T, job string, path string) []string {
+	s := store.NewStore()
+
+	for _, file := range []struct {
+		path   string
+		langID languages.LanguageID
+	}{{job, languages.NomadJob}, {path, languages.NomadVars}} {
+		src, err := os.ReadFile(file.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		doc := store.NewDocument(file.langID)
+		doc.ParseHCL(src, file.path)
+		s.AddFile(file.path, doc)
+	}
+
+	pathDec, err := decoder.NewDecoder(&s).Path(lang.Path{
+		Path:       path,
+		LanguageID: languages.NomadVars.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags, err := pathDec.ValidateFile(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summaries := make([]string, 0, len(diags))
+	for _, diag := range diags {
+		summaries = append(summaries, diag.Summary)
+	}
+
+	return summaries
+}
+
+func TestVariableValue(t *testing.T) {
+	expectCounts(t, validateVars(t, "testdata/vars/app.nomad.hcl", "testdata/vars/prod.vars.hcl"), map[string]int{
+		"Invalid value for variable": 1,
+		"Unexpected attribute":       1,
+	})
+
+	expectCounts(t, validateVars(t, "testdata/vars/app.nomad.hcl", "testdata/vars/dev.vars.hcl"), map[string]int{
+		"Invalid value for variable": 1,
+		"Variables not allowed":      1,
+	})
+
+	expectCounts(t, validateVars(t, "testdata/vars/app.nomad.hcl", "testdata/vars/staging.vars.hcl"), map[string]int{
+		"Invalid value for variable": 1,
+	})
+}
@@ -0,0 +1,137 @@
+package custom_validators
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl-lang/validator"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+)
+
+var _ validator.Validator = (*VolumeMount)(nil)
+
+// VolumeMount reports mounts of volumes which are not declared in the enclosing group
+// and group volumes which are not mounted by any task
+type VolumeMount struct{}
+
+func (v VolumeMount) Visit(ctx context.Context, node hclsyntax.Node, nodeSchema schema.Schema) (context.Context, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	group, ok := node.(*hclsyntax.Block)
+	if !ok || group.Type != "group" || nodeSchema == nil {
+		return ctx, diags
+	}
+
+	volumes := make([]*hclsyntax.Block, 0)
+	for _, block := range group.Body.Blocks {
+		if isDynamicBlock(block, "volume") {
+			// volumes declared by dynamic blocks can not be resolved
+			return ctx, diags
+		}
+
+		if block.Type == "volume" && len(block.Labels) > 0 {
+			volumes = append(volumes, block)
+		}
+	}
+
+	mounts, allStatic := volumeMounts(group.Body)
+
+	mounted := make([]string, 0, len(mounts))
+	for _, mount := range mounts {
+		name := mount.name
+		mounted = append(mounted, name)
+
+		declared := slices.ContainsFunc(volumes, func(volume *hclsyntax.Block) bool {
+			return volume.Labels[0] == name
+		})
+		if declared {
+			continue
+		}
+
+		rng := mount.expr.Range()
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Unknown volume %q", name),
+			Detail:   fmt.Sprintf("Volume %q is not declared in group %q, it has to be declared as `volume %q {}` in the group.", name, groupName(group), name),
+			Subject:  &rng,
+		})
+	}
+
+	if !allStatic {
+		return ctx, diags
+	}
+
+	for _, volume := range volumes {
+		if slices.Contains(mounted, volume.Labels[0]) {
+			continue
+		}
+
+		rng := volume.DefRange()
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  fmt.Sprintf("Volume %q is not mounted", volume.Labels[0]),
+			Detail:   fmt.Sprintf("No task of group %q mounts volume %q with a `volume_mount` block.", groupName(group), volume.Labels[0]),
+			Subject:  &rng,
+		})
+	}
+
+	return ctx, diags
+}
+
+type volumeMount struct {
+	name string
+	expr hclsyntax.Expression
+}
+
+// volumeMounts returns static volume names of all `volume_mount` blocks within the body,
+// allStatic is false when some of the names can not be determined
+func volumeMounts(body *hclsyntax.Body) (mounts []volumeMount, allStatic bool) {
+	allStatic = true
+
+	for _, block := range body.Blocks {
+		if isDynamicBlock(block, "volume_mount") {
+			allStatic = false
+			continue
+		}
+
+		if block.Type != "volume_mount" {
+			nested, nestedStatic := volumeMounts(block.Body)
+			mounts = append(mounts, nested...)
+			allStatic = allStatic && nestedStatic
+			continue
+		}
+
+		attr, ok := block.Body.Attributes["volume"]
+		if !ok {
+			continue
+		}
+
+		val, ok := eval.StaticValue(attr.Expr, cty.String)
+		if !ok {
+			allStatic = false
+			continue
+		}
+
+		mounts = append(mounts, volumeMount{name: val.AsString(), expr: attr.Expr})
+	}
+
+	return mounts, allStatic
+}
+
+func isDynamicBlock(block *hclsyntax.Block, blockType string) bool {
+	return block.Type == "dynamic" && len(block.Labels) > 0 && block.Labels[0] == blockType
+}
+
+func groupName(group *hclsyntax.Block) string {
+	if len(group.Labels) == 0 {
+		return ""
+	}
+
+	return group.Labels[0]
+}
@@ -0,0 +1,25 @@
+package custom_validators_test
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestVolumeMount(t *testing.T) {
+	summaries := validate(t, "testdata/volume_mounts.nomad.hcl")
+
+	expected := []string{
+		`Unknown volume "dta"`,
+		`Volume "backups" is not mounted`,
+	}
+
+	for _, summary := range expected {
+		if !slices.Contains(summaries, summary) {
+			t.Errorf("expected diagnostic: %s, recieved: %v", summary, summaries)
+		}
+	}
+
+	if slices.Contains(summaries, `Volume "data" is not mounted`) {
+		t.Error("expected mounted volume not to be reported")
+	}
+}
@@ -0,0 +1,106 @@
+package variables
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+)
+
+// DefaultValue returns the default value converted to the type of the variable,
+// the value is unknown when there is no default or it does not conform to the type
+func (v Variable) DefaultValue(newContext eval.NewContext) (cty.Value, hcl.Diagnostics) {
+	if v.Default == nil {
+		return cty.UnknownVal(v.Type), nil
+	}
+
+	val, diags := v.Default.Value(newContext(v.basedir(), nil))
+	if diags.HasErrors() {
+		return cty.UnknownVal(v.Type), nil
+	}
+
+	converted, err := convert.Convert(val, v.Type)
+	if err != nil {
+		return cty.UnknownVal(v.Type), hcl.Diagnostics{{
+			Severity: hcl.DiagWarning,
+			Summary:  "Invalid default value for variable",
+			Detail:   fmt.Sprintf("The default value of %q is not compatible with its type: %s", v.Name, err),
+			Subject:  v.Default.Range().Ptr(),
+		}}
+	}
+
+	return converted, nil
+}
+
+// Validate evaluates conditions of `validation` blocks with the value of the variable,
+// conditions which can not be decided statically are skipped
+func (v Variable) Validate(newContext eval.NewContext, val cty.Value, subject hcl.Range) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	ctx := newContext(v.basedir(), map[string]cty.Value{v.Name: val})
+
+	for _, validation := range v.Validations {
+		condition, ok := validation.Body.Attributes["condition"]
+		if !ok {
+			continue
+		}
+
+		result, condDiags := condition.Expr.Value(ctx)
+		if condDiags.HasErrors() || !result.IsWhollyKnown() || result.IsNull() {
+			continue
+		}
+
+		result, err := convert.Convert(result, cty.Bool)
+		if err != nil || result.True() {
+			continue
+		}
+
+		message := fmt.Sprintf("The value of %q does not satisfy the validation condition", v.Name)
+		if attr, ok := validation.Body.Attributes["error_message"]; ok {
+			if msg, ok := eval.StaticString(attr.Expr); ok {
+				message = msg
+			}
+		}
+
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid value for variable",
+			Detail:   message,
+			Subject:  subject.Ptr(),
+		})
+	}
+
+	return diags
+}
+
+// basedir is the directory of the file declaring the variable
+func (v Variable) basedir() string {
+	return filepath.Dir(v.DefRange.Filename)
+}
+
+// Values returns values of the variables, values of overrides (e.g. of var files)
+// take precedence over defaults and variables without a value are unknown
+func Values(newContext eval.NewContext, variables []Variable, overrides map[string]cty.Value) map[string]cty.Value {
+	values := make(map[string]cty.Value, len(variables))
+
+	for _, v := range variables {
+		if _, ok := values[v.Name]; ok {
+			continue
+		}
+
+		if override, ok := overrides[v.Name]; ok {
+			if val, err := convert.Convert(override, v.Type); err == nil {
+				values[v.Name] = val
+				continue
+			}
+		}
+
+		values[v.Name], _ = v.DefaultValue(newContext)
+	}
+
+	return values
+}
@@ -0,0 +1,126 @@
+// Package for input variables declared by jobs
+package variables
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/loczek/nomad-ls/internal/eval"
+	"github.com/loczek/nomad-ls/internal/schema/vars"
+)
+
+// Variable is an input variable declared by a `variable` block
+// or by an attribute of the `variables` block
+type Variable struct {
+	Name string
+	// Type is [cty.DynamicPseudoType] when any value is accepted
+	Type        cty.Type
+	Description string
+	// Default is the expression of the default value, nil when not set
+	Default hclsyntax.Expression
+	// Validations are `validation` blocks of the variable
+	Validations []*hclsyntax.Block
+	DefRange    hcl.Range
+}
+
+// Declared returns variables declared at the root of a job file
+func Declared(body *hclsyntax.Body) []Variable {
+	variables := make([]Variable, 0)
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "variable":
+			if v, ok := FromBlock(block); ok {
+				variables = append(variables, v)
+			}
+		case "variables":
+			for _, attr := range block.Body.Attributes {
+				variables = append(variables, Variable{
+					Name:     attr.Name,
+					Type:     cty.DynamicPseudoType,
+					Default:  attr.Expr,
+					DefRange: attr.NameRange,
+				})
+			}
+		}
+	}
+
+	return variables
+}
+
+// FromBlock returns the variable declared by a `variable` block
+func FromBlock(block *hclsyntax.Block) (Variable, bool) {
+	if block.Type != "variable" || len(block.Labels) != 1 {
+		return Variable{}, false
+	}
+
+	v := Variable{
+		Name:     block.Labels[0],
+		Type:     cty.DynamicPseudoType,
+		DefRange: block.DefRange(),
+	}
+
+	if attr, ok := block.Body.Attributes["type"]; ok {
+		if typ, diags := typeexpr.TypeConstraint(attr.Expr); !diags.HasErrors() {
+			v.Type = typ
+		}
+	}
+
+	if attr, ok := block.Body.Attributes["description"]; ok {
+		v.Description, _ = eval.StaticString(attr.Expr)
+	}
+
+	if attr, ok := block.Body.Attributes["default"]; ok {
+		v.Default = attr.Expr
+	}
+
+	for _, validation := range block.Body.Blocks {
+		if validation.Type == "validation" {
+			v.Validations = append(v.Validations, validation)
+		}
+	}
+
+	return v, true
+}
+
+// Schema returns the schema of var files assigning the variables,
+// the first declaration of a variable wins
+func Schema(variables []Variable) *schema.BodySchema {
+	if len(variables) == 0 {
+		return vars.RootSchema
+	}
+
+	attrs := make(map[string]*schema.AttributeSchema, len(variables))
+	for _, v := range variables {
+		if _, ok := attrs[v.Name]; ok {
+			continue
+		}
+
+		attrs[v.Name] = &schema.AttributeSchema{
+			Description: lang.Markdown(v.Describe()),
+			Constraint:  schema.LiteralType{Type: v.Type},
+			IsOptional:  true,
+		}
+	}
+
+	return &schema.BodySchema{
+		Description: vars.RootSchema.Description,
+		Attributes:  attrs,
+	}
+}
+
+// Describe returns the description of the variable as markdown
+func (v Variable) Describe() string {
+	description := v.Description
+	if v.Default == nil {
+		description = strings.TrimSpace(description + "\n\n_No default value, it has to be set by a var file or `-var`_")
+	}
+
+	return description
+}
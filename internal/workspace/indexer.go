@@ -0,0 +1,217 @@
+// Package for indexing Nomad files of workspace folders
+package workspace
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/lang"
+
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/store"
+)
+
+// skippedDirs are never walked, hidden directories are skipped as well
+var skippedDirs = []string{"node_modules", "vendor"}
+
+type Indexer struct {
+	store  *store.Store
+	logger slog.Logger
+
+	folders []string
+	mu      sync.RWMutex
+}
+
+func NewIndexer(store *store.Store, logger slog.Logger) *Indexer {
+	return &Indexer{
+		store:   store,
+		logger:  logger,
+		folders: make([]string, 0),
+	}
+}
+
+// AddFolder registers the folder, it is indexed by [Indexer.Index]
+func (i *Indexer) AddFolder(folder string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	folder = filepath.Clean(folder)
+	if !slices.Contains(i.folders, folder) {
+		i.folders = append(i.folders, folder)
+	}
+}
+
+// RemoveFolder unregisters the folder and removes its files which are not open
+func (i *Indexer) RemoveFolder(folder string) {
+	i.mu.Lock()
+	folder = filepath.Clean(folder)
+	i.folders = slices.DeleteFunc(i.folders, func(f string) bool {
+		return f == folder
+	})
+	i.mu.Unlock()
+
+	for path, doc := range i.store.Files() {
+		if !doc.IsOpen() && isWithin(folder, path) && !i.Contains(path) {
+			i.store.RemoveFile(path)
+		}
+	}
+}
+
+func (i *Indexer) Folders() []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return slices.Clone(i.folders)
+}
+
+// Contains reports whether path is within one of the workspace folders
+func (i *Indexer) Contains(path string) bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for _, folder := range i.folders {
+		if isWithin(folder, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IndexAll indexes all registered folders
+func (i *Indexer) IndexAll(ctx context.Context) {
+	for _, folder := range i.Folders() {
+		if err := i.Index(ctx, folder); err != nil {
+			i.logger.Error("failed to index workspace folder", "folder", folder, "error", err.Error())
+		}
+	}
+}
+
+// Index walks the folder and indexes every Nomad file in it
+func (i *Indexer) Index(ctx context.Context, folder string) error {
+	indexed := 0
+
+	err := filepath.WalkDir(folder, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// unreadable entries are skipped rather than failing the whole walk
+			return nil
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if entry.IsDir() {
+			if path != folder && isSkippedDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if _, ok := languages.FromFileName(entry.Name()); !ok {
+			return nil
+		}
+
+		if err := i.IndexFile(path); err != nil {
+			i.logger.Warn("failed to index file", "path", path, "error", err.Error())
+			return nil
+		}
+
+		indexed++
+
+		return nil
+	})
+
+	i.logger.Info("indexed workspace folder", "folder", folder, "files", indexed)
+
+	return err
+}
+
+// IndexFile reads the file from disk and adds it to the store,
+// files opened in the editor are left untouched
+func (i *Indexer) IndexFile(path string) error {
+	path = filepath.Clean(path)
+
+	langID, ok := languages.FromFileName(path)
+	if !ok {
+		return nil
+	}
+
+	if doc, err := i.store.GetFile(path); err == nil && doc.IsOpen() {
+		return nil
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	doc := store.NewDocument(langID)
+	doc.ParseHCL(src, path)
+
+	// the file may have been opened while it was read from disk
+	if !i.store.AddFileIfNotOpen(path, doc) {
+		return nil
+	}
+
+	dec := decoder.NewDecoder(i.store)
+	dec.SetContext(decoder.NewDecoderContext())
+
+	pathDec, err := dec.Path(lang.Path{
+		Path:       path,
+		LanguageID: langID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return doc.UpdateReferences(pathDec, path)
+}
+
+// IndexSiblings indexes job files in the directory of path which are not in the store yet,
+// var files are checked against variables declared by them
+func (i *Indexer) IndexSiblings(path string) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		if langID, ok := languages.FromFileName(entry.Name()); !ok || langID != languages.NomadJob {
+			continue
+		}
+
+		sibling := filepath.Join(filepath.Dir(path), entry.Name())
+		if i.store.Contains(sibling) {
+			continue
+		}
+
+		if err := i.IndexFile(sibling); err != nil {
+			i.logger.Warn("failed to index file", "path", sibling, "error", err.Error())
+		}
+	}
+}
+
+func isSkippedDir(name string) bool {
+	return strings.HasPrefix(name, ".") || slices.Contains(skippedDirs, name)
+}
+
+func isWithin(folder string, path string) bool {
+	rel, err := filepath.Rel(folder, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,86 @@
+package workspace
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/loczek/nomad-ls/internal/languages"
+	"github.com/loczek/nomad-ls/internal/store"
+)
+
+func TestIndex(t *testing.T) {
+	folder, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st := store.NewStore()
+	indexer := NewIndexer(&st, *slog.Default())
+	indexer.AddFolder(folder)
+	indexer.IndexAll(context.Background())
+
+	expected := map[string]languages.LanguageID{
+		filepath.Join(folder, "jobs", "api.nomad.hcl"): languages.NomadJob,
+		filepath.Join(folder, "default.nomad.np"):      languages.NomadNodePool,
+	}
+
+	files := st.Files()
+	if len(files) != len(expected) {
+		t.Fatalf("expected %d files, recieved %d", len(expected), len(files))
+	}
+
+	for path, lang := range expected {
+		doc, ok := files[path]
+		if !ok {
+			t.Fatalf("expected %s to be indexed", path)
+		}
+
+		if doc.Language != lang {
+			t.Errorf("expected: %s, recieved: %s", lang, doc.Language)
+		}
+	}
+
+	if len(files[filepath.Join(folder, "jobs", "api.nomad.hcl")].RefTargets()) == 0 {
+		t.Error("expected reference targets to be collected")
+	}
+
+	indexer.RemoveFolder(folder)
+
+	if len(st.Files()) != 0 {
+		t.Errorf("expected files to be removed with the folder, recieved %d", len(st.Files()))
+	}
+}
+
+func TestIndexFileKeepsOpenDocuments(t *testing.T) {
+	path, err := filepath.Abs(filepath.Join("testdata", "jobs", "api.nomad.hcl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st := store.NewStore()
+	indexer := NewIndexer(&st, *slog.Default())
+
+	open := store.NewDocument(languages.NomadJob)
+	open.SetOpen(true)
+	open.ParseHCL([]byte(`job "edited" {}`), path)
+	st.AddFile(path, open)
+
+	if err := indexer.IndexFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := st.GetFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc != open || string(doc.File().Bytes) != `job "edited" {}` {
+		t.Errorf("expected the open document to be kept, recieved: %s", doc.File().Bytes)
+	}
+
+	if st.AddFileIfNotOpen(path, store.NewDocument(languages.NomadJob)) {
+		t.Error("expected the open document not to be replaced")
+	}
+}
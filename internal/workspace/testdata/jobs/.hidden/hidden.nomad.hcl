@@ -0,0 +1,2 @@
+job "hidden" {
+}
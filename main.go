@@ -33,7 +33,8 @@ func init() {
 	flag.StringVar(&flags.socket, "socket", "", "port of the tcp socket as the transport method")
 
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: nomad-ls [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: nomad-ls [options]\n")
+		fmt.Fprintf(os.Stderr, "       nomad-ls render [options] <job file>\n\n")
 		fmt.Fprintf(os.Stderr, "Note: \"--stdio\", \"--pipe=...\" or \"--port=...\" must be defined\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
@@ -43,6 +44,10 @@ func init() {
 }
 
 func main() {
+	if flag.Arg(0) == "render" {
+		os.Exit(renderCommand(flag.Args()[1:]))
+	}
+
 	w := os.Stderr
 
 	var handler slog.Handler
@@ -100,7 +105,7 @@ func main() {
 	service := lsp.New(con, *logger)
 
 	con.Go(context.Background(), func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
-		go func() {
+		service.Go(req, func() {
 			logger.Info("Received request", slog.String("method", req.Method()))
 
 			resp, err := service.Handle(ctx, reply, req)
@@ -112,7 +117,7 @@ func main() {
 			if err != nil {
 				logger.Error("Received error from handler", "error", err.Error())
 			}
-		}()
+		})
 		return nil
 	})
 
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/loczek/nomad-ls/internal/render"
+)
+
+// stringsFlag collects values of a flag passed multiple times
+type stringsFlag []string
+
+func (f *stringsFlag) String() string { return strings.Join(*f, ", ") }
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// renderCommand prints the job the same way as `nomad job run -output`
+// without requiring a Nomad cluster
+func renderCommand(args []string) int {
+	var varFiles, vars stringsFlag
+
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	flags.Var(&varFiles, "var-file", "`path` of a var file, can be passed multiple times")
+	flags.Var(&vars, "var", "variable as `<name>=<value>`, can be passed multiple times")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: nomad-ls render [options] <job file>\n\n")
+		fmt.Fprintf(os.Stderr, "Renders the job into the JSON structure of the Nomad API.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flags.PrintDefaults()
+	}
+
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	path := flags.Arg(0)
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// var files are relative to the working directory rather than the job
+	for i, varFile := range varFiles {
+		if abs, err := filepath.Abs(varFile); err == nil {
+			varFiles[i] = abs
+		}
+	}
+
+	job, diags := render.Job(path, src, render.Options{
+		VarFiles: varFiles,
+		Vars:     vars,
+	})
+
+	if len(diags) > 0 {
+		writer := hcl.NewDiagnosticTextWriter(os.Stderr, diagnosticFiles(diags), 78, false)
+		writer.WriteDiagnostics(diags)
+	}
+
+	if diags.HasErrors() {
+		return 1
+	}
+
+	out, err := render.JSON(job)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Println(string(out))
+
+	return 0
+}
+
+// diagnosticFiles parses the files of diagnostics to show their source snippets
+func diagnosticFiles(diags hcl.Diagnostics) map[string]*hcl.File {
+	files := make(map[string]*hcl.File)
+
+	for _, diag := range diags {
+		if diag.Subject == nil {
+			continue
+		}
+
+		name := diag.Subject.Filename
+		if _, ok := files[name]; ok {
+			continue
+		}
+
+		src, err := os.ReadFile(name)
+		if err != nil {
+			continue
+		}
+
+		files[name], _ = hclsyntax.ParseConfig(src, name, hcl.InitialPos)
+	}
+
+	return files
+}